@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // readArgs() takes no arguements and returns filled launchArgs struct unless
@@ -13,13 +14,33 @@ func readArgs(d *DDConfig) {
 	d.traceMsg("Called readArgs")
 	// Read in the supported command-line options
 	var version, help, v, h bool
+	var output, only, skip string
 	flag.BoolVar(&d.defInstall, "default", false, "Do an install based on default config values")
 	flag.BoolVar(&version, "version", false, "Print the version and exit")
 	flag.BoolVar(&v, "v", false, "Print the version and exit")
 	flag.BoolVar(&help, "help", false, "Print the help message and exit")
 	flag.BoolVar(&h, "h", false, "Print the help message and exit")
+	flag.BoolVar(&d.follow, "follow", false, "Stream the output of each command as it runs")
+	flag.BoolVar(&d.follow, "verbose", false, "Stream the output of each command as it runs")
+	flag.StringVar(&output, "output", "text", "Output format, either text or json")
+	flag.BoolVar(&d.confirmDrop, "confirm-drop", false, "Allow Install.DB.Drop to drop a database that has tables")
+	flag.StringVar(&only, "only", "", "Comma separated list of install phases to run")
+	flag.StringVar(&skip, "skip", "", "Comma separated list of install phases to skip")
 	flag.Parse()
 
+	// Flags can also follow a subcommand e.g. godojo restore --confirm-drop backup.tar.gz
+	if flag.NArg() > 0 {
+		d.subCmd = flag.Arg(0)
+		_ = flag.CommandLine.Parse(flag.Args()[1:])
+		d.subArgs = flag.Args()
+	}
+
+	// Set the output format before anything else is printed
+	setOutput(d, output)
+
+	// Determine which install phases to run
+	selectPhases(d, only, skip)
+
 	// Print help
 	if help || h {
 		printHelp()
@@ -31,6 +52,9 @@ func readArgs(d *DDConfig) {
 		os.Exit(0)
 	}
 
+	// Check the subcommand and its arguments
+	checkSubCmd(d)
+
 	// Handle special install case of default installs
 	if d.defInstall {
 		return
@@ -41,10 +65,11 @@ func readArgs(d *DDConfig) {
 	if err != nil {
 		fmt.Println("Unable to determine current working directory, exiting...")
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitGeneral)
 	}
 	_, err = os.Stat(path + "/" + d.cf)
-	if err != nil {
+	// A restore can use the config in the backup archive
+	if err != nil && d.subCmd != "restore" {
 		// No config file found, so create one and exit
 		writeDefaultConfig(d.cf, true)
 	}
@@ -52,6 +77,27 @@ func readArgs(d *DDConfig) {
 	d.traceMsg("Reached the end of readArgs")
 }
 
+// checkSubCmd takes a pointer to a DDConfig struct and exits if the
+// subcommand or the number of arguments to it isn't valid
+func checkSubCmd(d *DDConfig) {
+	ok := false
+	switch d.subCmd {
+	case "":
+		return
+	case "backup":
+		ok = len(d.subArgs) <= 1
+	case "restore":
+		ok = len(d.subArgs) == 1
+	default:
+		fmt.Printf("Unknown command %s\nValid commands are backup and restore, see --help\n", d.subCmd)
+		d.exitWith(exitConfig)
+	}
+	if !ok || d.defInstall {
+		fmt.Println("Usage: godojo backup [archive.tar.gz] or godojo restore archive.tar.gz, see --help")
+		d.exitWith(exitConfig)
+	}
+}
+
 // printHelp takes no arguements and prints godojo's help content to stdout
 func printHelp() {
 	// Output the help info
@@ -59,14 +105,38 @@ func printHelp() {
 	fmt.Println("Usage of godojo")
 	fmt.Println("")
 	fmt.Println("./godojo [optional arguments]")
+	fmt.Println("./godojo backup [optional arguments] [archive.tar.gz]")
+	fmt.Println("./godojo restore [optional arguments] archive.tar.gz")
 	fmt.Println("")
 	fmt.Println("  [No arguments]")
 	fmt.Println("        Check for a dojoConfig.yml file in the current working directory")
 	fmt.Println("        If found, use those values to configure the installation")
 	fmt.Println("        If NOT found, create a default dojoConfig.yml in the current working directory and exit")
+	fmt.Println("  backup [archive.tar.gz]")
+	fmt.Println("        Back up the DefectDojo install described by dojoConfig.yml to a single archive with its")
+	fmt.Println("        database, uploaded files, settings and config.  Defaults to a timestamped archive in")
+	fmt.Println("        Install.DB.BackupDir")
+	fmt.Println("  restore archive.tar.gz")
+	fmt.Println("        Restore an archive from godojo backup, installing DefectDojo first if needed.  Uses")
+	fmt.Println("        dojoConfig.yml in the current directory if there is one, otherwise the config in the archive")
 	fmt.Println("  -default")
 	fmt.Println("        OPTIONAL - Do an install based on the default dojoConfig.yml values")
 	fmt.Println("                   Must be used alone and without other arguments")
+	fmt.Println("  -follow, -verbose")
+	fmt.Println("        OPTIONAL - Stream the output of each OS command live with a header for each phase")
+	fmt.Println("                   instead of showing a progress display.  Output is still written to the logs")
+	fmt.Println("  -only phase1,phase2")
+	fmt.Println("        OPTIONAL - Only run the listed install phases, in install order.  Valid phases are:")
+	fmt.Println("                   " + strings.ReplaceAll(phaseNames(), ",", ", "))
+	fmt.Println("                   Phases check that earlier phases they depend on have been completed")
+	fmt.Println("  -skip phase1,phase2")
+	fmt.Println("        OPTIONAL - Skip the listed install phases, valid phases are the same as -only")
+	fmt.Println("  -confirm-drop")
+	fmt.Println("        OPTIONAL - Allow Install.DB.Drop to drop an existing database that has tables.  The database")
+	fmt.Println("                   is backed up to Install.DB.BackupDir first, the backup path is in the summary")
+	fmt.Println("  -output [text|json]")
+	fmt.Println("        OPTIONAL - Set the format of godojo's output, defaults to text.  json writes one JSON")
+	fmt.Println("                   event per line to stdout for CI and automation - see README for the schema")
 	fmt.Println("  -help, -h")
 	fmt.Println("        Print this help message and exit, ignoring all other arguments")
 	fmt.Println("  -version, -v")
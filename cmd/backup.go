@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Handles godojo backup which writes a DefectDojo instance to a single
+// archive that godojo restore can rebuild on the same or a fresh host
+
+// Version of the archive layout, bump this if restore can't read an archive
+// written by an older godojo
+const archiveFormat = 1
+
+// Paths inside a backup archive
+const (
+	archManifest = "manifest.json"
+	archConfig   = "config/dojoConfig.yml"
+	archSettings = "settings/"
+	archMedia    = "media/"
+)
+
+// Settings files for DefectDojo that are backed up, the keys in .env.prod
+// are needed to decrypt credentials stored in the database
+var settingsFiles = []string{".env.prod", "local_settings.py"}
+
+// backupManifest describes the contents of a backup archive
+type backupManifest struct {
+	Format      int            `json:"format"`
+	Created     string         `json:"created"`
+	Godojo      string         `json:"godojo_version"`
+	DojoVersion string         `json:"defectdojo_version"`
+	Host        string         `json:"hostname"`
+	Engine      string         `json:"db_engine"`
+	DBName      string         `json:"db_name"`
+	Files       []manifestFile `json:"files"`
+}
+
+// manifestFile is a file in a backup archive with its checksum
+type manifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// archiveWriter writes files to a gzip compressed tar archive, recording
+// each one in the manifest
+type archiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+	m  backupManifest
+}
+
+// runBackup takes a pointer to a DDConfig struct and backs up the DefectDojo
+// instance described by dojoConfig.yml
+func runBackup(d *DDConfig) {
+	d.cmdLogger = setCmdLogging(d)
+	lockRoot(d)
+
+	err := needSettings(d)
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to back up DefectDojo: %+v", err))
+		d.exitWith(exitConfig)
+	}
+
+	d.inPhase("backup", func() {
+		d.sectionMsg("Backing up DefectDojo")
+		err = backupInstance(d)
+		if err != nil {
+			d.errorMsg(fmt.Sprintf("%+v", err))
+			d.exitWith(exitBackup)
+		}
+	})
+
+	d.statusMsg(fmt.Sprintf("\nSuccessfully backed up DefectDojo to %s", d.archive))
+	d.releaseLocks()
+	d.summary("ok", "DefectDojo backed up")
+}
+
+// backupInstance writes the database, settings, runtime config and media of
+// DefectDojo to an archive along with a manifest of checksums
+func backupInstance(d *DDConfig) error {
+	dst := filepath.Join(backupDir(d), "defectdojo-"+time.Now().Format("20060102-150405")+".tar.gz")
+	if len(d.subArgs) > 0 {
+		dst = d.subArgs[0]
+	}
+	err := os.MkdirAll(filepath.Dir(dst), 0700)
+	if err != nil {
+		return fmt.Errorf("Unable to create the directory for %s: %w", dst, err)
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("Unable to create the backup archive: %w", err)
+	}
+	defer f.Close()
+	d.traceMsg(fmt.Sprintf("Writing backup archive %s", dst))
+
+	a := newArchiveWriter(f)
+	host, _ := os.Hostname()
+	a.m.Godojo = d.ver
+	a.m.DojoVersion = dojoVersion(d)
+	a.m.Host = host
+	a.m.Engine = d.conf.Install.DB.Engine
+	a.m.DBName = d.conf.Install.DB.Name
+
+	err = backupContents(d, a, filepath.Dir(dst))
+	if err == nil {
+		err = a.close()
+	}
+	if err != nil {
+		// Don't leave a partial archive that looks usable
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	err = writeChecksum(dst)
+	if err != nil {
+		return err
+	}
+	d.archive = dst
+
+	return nil
+}
+
+// backupContents adds each part of the DefectDojo instance to the archive.
+// The database is dumped first, uploads made while the backup runs may be
+// in the archive without matching database records.
+func backupContents(d *DDConfig, a *archiveWriter, tmpDir string) error {
+	d.statusMsg(fmt.Sprintf("Dumping %s database %s", d.conf.Install.DB.Engine, d.conf.Install.DB.Name))
+	tmp, err := os.CreateTemp(tmpDir, ".godojo-dump-*")
+	if err != nil {
+		return fmt.Errorf("Unable to create a file for the database dump: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	creds := map[string]string{"user": d.conf.Install.DB.User, "pass": d.conf.Install.DB.Pass}
+	err = dumpDB(d, creds, tmp)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("Unable to dump database %s: %w", d.conf.Install.DB.Name, err)
+	}
+	err = a.addFile(dbArchivePath(d.conf.Install.DB.Engine), tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	d.statusMsg("Adding settings and the runtime config")
+	for _, s := range settingsFiles {
+		p := filepath.Join(settingsDir(d), s)
+		if _, err := os.Stat(p); err != nil {
+			d.traceMsg(fmt.Sprintf("No %s to back up", p))
+			continue
+		}
+		err = a.addFile(archSettings+s, p)
+		if err != nil {
+			return err
+		}
+	}
+	// Written by writeFinalConfig for this run
+	err = a.addFile(archConfig, "runtime-install-config.yml")
+	if err != nil {
+		return err
+	}
+
+	media := mediaDir(d)
+	if _, err := os.Stat(media); err != nil {
+		d.traceMsg(fmt.Sprintf("No media directory at %s to back up", media))
+		return nil
+	}
+	d.statusMsg(fmt.Sprintf("Adding uploaded files from %s", media))
+	return filepath.WalkDir(media, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !e.Type().IsRegular() {
+			if !e.IsDir() {
+				d.traceMsg(fmt.Sprintf("Skipping %s since it isn't a regular file", p))
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(media, p)
+		if err != nil {
+			return err
+		}
+		return a.addFile(archMedia+filepath.ToSlash(rel), p)
+	})
+}
+
+// newArchiveWriter returns an archiveWriter writing to w
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+		m:  backupManifest{Format: archiveFormat, Created: time.Now().UTC().Format(time.RFC3339)},
+	}
+}
+
+// addFile adds the file at p to the archive as name
+func (a *archiveWriter) addFile(name string, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("Unable to add %s to the backup: %w", p, err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("Unable to add %s to the backup: %w", p, err)
+	}
+
+	h := sha256.New()
+	err = a.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    int64(fi.Mode().Perm()),
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	})
+	if err == nil {
+		_, err = io.Copy(io.MultiWriter(a.tw, h), f)
+	}
+	if err != nil {
+		return fmt.Errorf("Unable to add %s to the backup: %w", p, err)
+	}
+	a.m.Files = append(a.m.Files, manifestFile{Path: name, Size: fi.Size(), SHA256: hex.EncodeToString(h.Sum(nil))})
+
+	return nil
+}
+
+// close writes the manifest as the last file and finishes the archive
+func (a *archiveWriter) close() error {
+	b, err := json.MarshalIndent(a.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = a.tw.WriteHeader(&tar.Header{Name: archManifest, Mode: 0600, Size: int64(len(b)), ModTime: time.Now()})
+	if err == nil {
+		_, err = a.tw.Write(b)
+	}
+	if err == nil {
+		err = a.tw.Close()
+	}
+	if err == nil {
+		err = a.gz.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("Unable to finish the backup archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeChecksum writes a sha256sum compatible checksum of the archive at p
+// to p.sha256
+func writeChecksum(p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p+".sha256", []byte(hex.EncodeToString(h.Sum(nil))+"  "+filepath.Base(p)+"\n"), 0600)
+}
+
+// dbArchivePath returns the path of the database dump in a backup archive
+func dbArchivePath(engine string) string {
+	if engine == "SQLite" {
+		return path.Join("db", "defectdojo.sqlite3")
+	}
+
+	return path.Join("db", "defectdojo.sql")
+}
+
+// settingsDir returns the directory holding DefectDojo's settings files
+func settingsDir(d *DDConfig) string {
+	return filepath.Join(d.conf.Install.Root, d.conf.Install.Source, d.conf.Install.App, "settings")
+}
+
+// mediaDir returns where DefectDojo stores uploaded files, DefectDojo's
+// default is media in the source directory
+func mediaDir(d *DDConfig) string {
+	if d.conf.Settings.MediaRoot != "" {
+		return d.conf.Settings.MediaRoot
+	}
+
+	return filepath.Join(d.conf.Install.Root, d.conf.Install.Source, "media")
+}
+
+// Matches the version in DefectDojo's dojo/__init__.py
+var dojoVerRE = regexp.MustCompile(`^__version__\s*=\s*["']([^"']+)["']`)
+
+// dojoVersion returns the version of the installed DefectDojo source or an
+// empty string if it can't be determined
+func dojoVersion(d *DDConfig) string {
+	f, err := os.Open(filepath.Join(d.conf.Install.Root, d.conf.Install.Source, d.conf.Install.App, "__init__.py"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if m := dojoVerRE.FindStringSubmatch(strings.TrimSpace(s.Text())); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"db/defectdojo.sql":   "CREATE TABLE dojo_finding (id int);\n",
+		"settings/.env.prod":  "DD_SECRET_KEY=abc\n",
+		"media/uploads/a.txt": "attached file",
+		archConfig:            "Install:\n  Root: /opt/dojo\n",
+	}
+
+	for _, tamper := range []bool{false, true} {
+		var buf bytes.Buffer
+		a := newArchiveWriter(&buf)
+		a.m.Engine = "PostgreSQL"
+		for name, body := range files {
+			p := filepath.Join(src, filepath.Base(name))
+			if err := os.WriteFile(p, []byte(body), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err := a.addFile(name, p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if tamper {
+			a.m.Files[0].SHA256 = "0000"
+		}
+		if err := a.close(); err != nil {
+			t.Fatal(err)
+		}
+
+		arch := filepath.Join(t.TempDir(), "backup.tar.gz")
+		if err := os.WriteFile(arch, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if b, err := archivedConfig(arch); err != nil || string(b) != files[archConfig] {
+			t.Errorf("archivedConfig returned %q, %v", b, err)
+		}
+
+		dst := t.TempDir()
+		got, err := extractArchive(&buf, dst)
+		if err != nil {
+			t.Fatalf("Unable to extract archive: %v", err)
+		}
+		m, err := verifyManifest(dst, got)
+		if tamper {
+			if err == nil {
+				t.Error("Expecting a checksum mismatch to fail verification")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expecting the archive to verify, got %v", err)
+		}
+		if m.Format != archiveFormat || len(m.Files) != len(files) {
+			t.Errorf("Unexpected manifest %+v", m)
+		}
+		b, _ := os.ReadFile(filepath.Join(dst, "media", "uploads", "a.txt"))
+		if string(b) != files["media/uploads/a.txt"] {
+			t.Errorf("Extracted media file has %q", b)
+		}
+	}
+}
+
+func TestNeedMigrate(t *testing.T) {
+	cases := []struct {
+		from, to string
+		migrate  bool
+		err      bool
+	}{
+		{"2.4.1", "2.4.1", false, false},
+		{"2.4.1", "2.10.0", true, false},
+		{"2.10.0", "2.4.1", false, true},
+		{"2.4", "2.4.0", false, false},
+		{"", "2.4.1", true, false},
+	}
+	for _, c := range cases {
+		migrate, err := needMigrate(c.from, c.to)
+		if migrate != c.migrate || (err != nil) != c.err {
+			t.Errorf("needMigrate(%q, %q) = %v, %v", c.from, c.to, migrate, err)
+		}
+	}
+}
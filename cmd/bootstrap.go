@@ -11,9 +11,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/briandowns/spinner"
-	"github.com/defectdojo/godojo/distros"
-	c "github.com/mtesauro/commandeer"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 )
@@ -23,55 +20,44 @@ import (
 func bootstrapInstall(d *DDConfig, t *targetOS) {
 	d.sectionMsg("Bootstrapping the godojo installer")
 
-	// Create new boostrap command package
-	cBootstrap := c.NewPkg("bootstrap")
+	// Get the bootstrap commands for the target OS
+	tCmds := distroCmds(d, t, "bootstrap")
 
-	// Get commands for the right distro
-	switch {
-	case strings.ToLower(t.distro) == "ubuntu":
-		d.traceMsg("Searching for commands for bootstrapping Ubuntu")
-		err := distros.GetUbuntu(cBootstrap, t.id)
-		if err != nil {
-			fmt.Printf("Error searching for commands to bootstrap target OS %s\n", t.id)
-			os.Exit(1)
-		}
-	case strings.ToLower(t.distro) == "rhel":
-		d.traceMsg("Searching for commands for bootstrapping RHEL")
-		err := distros.GetRHEL(cBootstrap, t.id)
-		if err != nil {
-			fmt.Printf("Error searching for commands to bootstrap target OS %s\n", t.id)
-			os.Exit(1)
-		}
-	default:
-		d.traceMsg(fmt.Sprintf("Distro identified (%s) is not supported", t.id))
-		fmt.Printf("Distro identified by godojo (%s) is not supported, exiting...\n", t.id)
-		os.Exit(1)
+	// Alpine doesn't include bash which is used to run the commands below
+	if t.distro == "alpine" {
+		alpineBash(d)
 	}
 
-	// Start the spinner
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Bootstrapping..."
-	d.spin.Start()
-	// Run the boostrapping commands for the target OS
-	d.traceMsg(fmt.Sprintf("Getting commands to bootstrap %s", t.id))
-	tCmds, err := distros.CmdsForTarget(cBootstrap, t.id)
-	if err != nil {
-		fmt.Printf("Error getting commands to bootstrap target OS %s\n", t.id)
-		os.Exit(1)
+	// Some distros need repos or modules enabled first e.g. CRB on Rocky Linux
+	variant := t.variant
+	if variant == "" {
+		variant = t.distro
 	}
+	tCmds = append(targetDistro(d, t).Prepare(variant, t.release), tCmds...)
 
-	for i := range tCmds {
-		sendCmd(d,
-			d.cmdLogger,
-			tCmds[i].Cmd,
-			tCmds[i].Errmsg,
-			tCmds[i].Hard)
-	}
-	d.spin.Stop()
+	runPhaseCmds(d, "bootstrap", "Bootstrapping...", tCmds)
 	d.statusMsg("Boostraping godojo installer complete")
 
 }
 
+// alpineBash installs bash with apk if it isn't already installed
+func alpineBash(d *DDConfig) {
+	_, err := exec.LookPath("bash")
+	if err == nil {
+		return
+	}
+
+	d.traceMsg("bash not found, installing it with apk")
+	d.lastCmd = "apk add --no-cache bash"
+	d.cmdLogger.Printf("[godojo] # %s\n", d.lastCmd)
+	out, err := exec.Command("apk", "add", "--no-cache", "bash").CombinedOutput()
+	d.cmdLogger.Printf("%s\n", string(out))
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to install bash with apk, error was: %+v", err))
+		d.exitWith(exitOSCmd)
+	}
+}
+
 // validPython checks to ensure the correct version of Python is available
 func validPython(d *DDConfig) {
 	d.sectionMsg("Checking for Python 3.11")
@@ -81,7 +67,7 @@ func validPython(d *DDConfig) {
 		d.errorMsg("Python 3.11 wasn't found, quitting installer\n" +
 			"         Please set PYPATH to a Python 3.11.x installation\n" +
 			"         And re-run godojo like: 'PYPATH=\"/path/to/python3.11\" ./godojo'")
-		os.Exit(1)
+		d.exitWith(exitUnsupported)
 	}
 }
 
@@ -91,7 +77,7 @@ func checkPythonVersion(d *DDConfig) bool {
 	_, err := exec.LookPath("python3")
 	if err != nil {
 		d.errorMsg(fmt.Sprintf("Unable to find python binary in the path. Error was: %+v", err))
-		os.Exit(1)
+		d.exitWith(exitUnsupported)
 	}
 
 	// Execute the python3 command with --version to get the version
@@ -101,7 +87,7 @@ func checkPythonVersion(d *DDConfig) bool {
 	cmdOut, err := runCmd.CombinedOutput()
 	if err != nil {
 		d.errorMsg(fmt.Sprintf("Failed to run python3 command, error was: %+v", err))
-		os.Exit(1)
+		d.exitWith(exitUnsupported)
 	}
 
 	// Parse command output for the strings we need
@@ -129,7 +115,7 @@ func downloadDojo(d *DDConfig) {
 			err := getDojoSource(d)
 			if err != nil {
 				d.errorMsg(fmt.Sprintf("Error attempting to install Dojo source was:\n    %+v", err))
-				os.Exit(1)
+				d.exitWith(exitNetwork)
 			}
 		} else {
 			// Download Dojo source as a Github release tarball
@@ -138,7 +124,7 @@ func downloadDojo(d *DDConfig) {
 			err := getDojoRelease(d)
 			if err != nil {
 				d.errorMsg(fmt.Sprintf("Error attempting to install Dojo from a release tarball was:\n    %+v", err))
-				os.Exit(1)
+				d.exitWith(exitNetwork)
 			}
 		}
 	} else {
@@ -151,9 +137,7 @@ func downloadDojo(d *DDConfig) {
 // and places it in the specified dojoSource directory (default is /opt/dojo)
 func getDojoRelease(d *DDConfig) error {
 	d.statusMsg(fmt.Sprintf("Downloading the configured release of DefectDojo => version %+v", d.conf.Install.Version))
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Downloading release..."
-	d.spin.Start()
+	d.startSpin("Downloading release...")
 
 	// Create the directory to clone the source into if it doesn't exist already
 	d.traceMsg("Creating the Dojo root directory if it doesn't exist already")
@@ -182,7 +166,7 @@ func getDojoRelease(d *DDConfig) error {
 		if err != nil {
 			return err
 		}
-		d.spin.Stop()
+		d.stopSpin()
 		d.statusMsg("Tarball already downloaded and extracted the DefectDojo release file")
 		return nil
 	}
@@ -202,7 +186,7 @@ func getDojoRelease(d *DDConfig) error {
 			err := resp.Body.Close()
 			if err != nil {
 				d.traceMsg(fmt.Sprintf("Error closing response.\nError was: %v", err))
-				os.Exit(1)
+				d.exitWith(exitNetwork)
 			}
 		}()
 	}
@@ -238,7 +222,7 @@ func getDojoRelease(d *DDConfig) error {
 	}
 
 	// Successfully extracted the file, return nil
-	d.spin.Stop()
+	d.stopSpin()
 	d.statusMsg("Successfully downloaded and extracted the DefectDojo release file")
 	return nil
 }
@@ -274,8 +258,6 @@ func extractRelease(d *DDConfig, t string) error {
 // (default is /opt/dojo)
 func getDojoSource(d *DDConfig) error {
 	d.statusMsg("Downloading DefectDojo source as a branch or commit from the repo directly")
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Downloading DefectDojo source..."
 
 	// Create the directory to clone the source into if it doesn't exist already
 	d.traceMsg("Creating source directory if it doesn't exist already")
@@ -298,7 +280,7 @@ func getDojoSource(d *DDConfig) error {
 	if len(d.conf.Install.SourceCommit) > 0 {
 		// Commit is set, so it will be used and branch ignored
 		d.statusMsg(fmt.Sprintf("Dojo will be installed from commit %+v", d.conf.Install.SourceCommit))
-		d.spin.Start()
+		d.startSpin("Downloading DefectDojo source...")
 
 		// Do the initial clone of DefectDojo from Github
 		d.traceMsg(fmt.Sprintf("Initial clone of %+v", d.cloneURL))
@@ -328,7 +310,7 @@ func getDojoSource(d *DDConfig) error {
 			return err
 		}
 		d.statusMsg(fmt.Sprintf("DefectDojo will be installed from %+v branch", d.conf.Install.SourceBranch))
-		d.spin.Start()
+		d.startSpin("Downloading DefectDojo source...")
 
 		// Check out a specific branch
 		// Note: Branch and tag references are a bit odd, see https://github.com/src-d/go-git/blob/master/_examples/branch/main.go#L33
@@ -347,7 +329,7 @@ func getDojoSource(d *DDConfig) error {
 	}
 
 	// Successfully checked out the configured source, return nil
-	d.spin.Stop()
+	d.stopSpin()
 	d.statusMsg("Successfully checked out the configured DefectDojo source")
 	return nil
 }
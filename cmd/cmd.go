@@ -8,6 +8,13 @@ func Main() {
 	// Prepeare the installer
 	prepInstaller(&defaults)
 
-	// Start the installation
-	run(&defaults)
+	// Run the subcommand or start the installation
+	switch defaults.subCmd {
+	case "backup":
+		runBackup(&defaults)
+	case "restore":
+		runRestore(&defaults)
+	default:
+		run(&defaults)
+	}
 }
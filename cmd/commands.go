@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -20,26 +21,165 @@ type osCmds struct {
 	hard   []bool   // Flag to know if an error on the matching command is fatal
 }
 
+// How long an interrupted godojo waits for the running command to exit
+// before killing it
+var childGrace = 30 * time.Second
+
+// shellCmd returns the command to run cmd with the install target's shell
+func shellCmd(d *DDConfig, cmd string) *exec.Cmd {
+	sh := d.shell
+	if sh == "" {
+		sh = "bash"
+	}
+
+	return exec.Command(sh, "-c", cmd)
+}
+
+// runShell runs a command from shellCmd, killing it and any processes it
+// started if it runs longer than timeout.  A timeout of 0 waits forever.
+func runShell(d *DDConfig, runCmd *exec.Cmd, timeout time.Duration) error {
+	err := d.startChild(runCmd)
+	if err != nil {
+		return err
+	}
+	var fired int32
+	if timeout > 0 {
+		t := time.AfterFunc(timeout, func() {
+			atomic.StoreInt32(&fired, 1)
+			_ = syscall.Kill(-runCmd.Process.Pid, syscall.SIGKILL)
+		})
+		defer t.Stop()
+	}
+	err = d.waitChild(runCmd)
+	if err != nil && atomic.LoadInt32(&fired) == 1 {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+
+	return err
+}
+
+// startChild starts an OS command in its own process group and records it
+// as the running command so an interrupted godojo can stop it, and anything
+// it started, before releasing its locks
+func (d *DDConfig) startChild(runCmd *exec.Cmd) error {
+	if runCmd.SysProcAttr == nil {
+		runCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	runCmd.SysProcAttr.Setpgid = true
+
+	d.childMu.Lock()
+	if d.stopping {
+		// godojo is exiting after a signal, don't start anything new
+		d.childMu.Unlock()
+		select {}
+	}
+	err := runCmd.Start()
+	if err == nil {
+		d.child, d.childDone = runCmd, make(chan struct{})
+	}
+	d.childMu.Unlock()
+
+	return err
+}
+
+// waitChild waits for a command from startChild to exit
+func (d *DDConfig) waitChild(runCmd *exec.Cmd) error {
+	err := runCmd.Wait()
+	d.childMu.Lock()
+	if d.child == runCmd {
+		close(d.childDone)
+		d.child, d.childDone = nil, nil
+	}
+	stopping := d.stopping
+	d.childMu.Unlock()
+	if stopping {
+		// The signal handler exits godojo now the command is gone
+		select {}
+	}
+
+	return err
+}
+
+// runChild runs an OS command like exec.Cmd.Run using startChild
+func (d *DDConfig) runChild(runCmd *exec.Cmd) error {
+	err := d.startChild(runCmd)
+	if err != nil {
+		return err
+	}
+
+	return d.waitChild(runCmd)
+}
+
+// stopChild sends s to the process group of the running command and waits
+// for it to exit, killing it if it's still running after childGrace.  No
+// new commands are started once it's called.
+func (d *DDConfig) stopChild(s os.Signal) {
+	d.childMu.Lock()
+	d.stopping = true
+	child, done := d.child, d.childDone
+	d.childMu.Unlock()
+	if child == nil {
+		return
+	}
+
+	sig, ok := s.(syscall.Signal)
+	if !ok {
+		sig = syscall.SIGTERM
+	}
+	d.traceMsg(fmt.Sprintf("Sending %s to the running command, PID %d", s, child.Process.Pid))
+	_ = syscall.Kill(-child.Process.Pid, sig)
+	select {
+	case <-done:
+	case <-time.After(childGrace):
+		d.warnMsg(fmt.Sprintf("The running command is still running after %s, killing it", childGrace))
+		_ = syscall.Kill(-child.Process.Pid, syscall.SIGKILL)
+		<-done
+	}
+}
+
 // TODO: Document this and/or move it to a separate package
-func sendCmd(d *DDConfig, o *log.Logger, cmd string, lerr string, hard bool) {
+func sendCmd(d *DDConfig, o *log.Logger, cmd string, lerr string, hard bool, timeout time.Duration) error {
 	// Setup command
-	runCmd := exec.Command("bash", "-c", cmd)
+	runCmd := shellCmd(d, cmd)
+	d.lastCmd = cmd
 	d.cmdLogger.Printf("[godojo] # %s\n", d.redactatron(cmd, d.redact))
 
-	// Run and gather its output
-	cmdOut, err := runCmd.CombinedOutput()
+	// Stream the output live if --follow was used, otherwise gather it for the log
+	var err error
+	if d.follow && !d.quiet {
+		err = followCmd(d, runCmd, timeout)
+	} else {
+		var cmdOut bytes.Buffer
+		runCmd.Stdout = &cmdOut
+		runCmd.Stderr = &cmdOut
+		err = runShell(d, runCmd, timeout)
+		d.cmdLogger.Printf("%s\n", cmdOut.String())
+	}
 	if err != nil {
 		d.errorMsg(fmt.Sprintf("%s - Failed to run OS command %+v, error was: %+v",
 			timeStamp(), d.redactatron(cmd, d.redact), err))
 		if hard {
 			// Exit on hard aka fatal errors
-			os.Exit(1)
+			d.exitWith(d.cmdExitCode())
 		}
 	}
-	d.cmdLogger.Printf("%s\n", string(cmdOut))
-	if err != nil {
-		d.errorMsg(fmt.Sprintf("Failed to write to OS command log file, error was: %+v", err))
-	}
+
+	return err
+}
+
+// followCmd runs the provided command, streaming stdout and stderr to the
+// console as well as the command log
+func followCmd(d *DDConfig, runCmd *exec.Cmd, timeout time.Duration) error {
+	// Hook up stdout and stderr to both the command log and the console
+	console := newLineRedactor(d, os.Stdout)
+	out := io.MultiWriter(d.cmdLogger.Writer(), console)
+	runCmd.Stdout = out
+	runCmd.Stderr = out
+
+	err := runShell(d, runCmd, timeout)
+	console.Flush()
+
+	return err
 }
 
 // TODO: Document this and/or move it to a separate package
@@ -47,6 +187,7 @@ func tryCmd(d *DDConfig, cmd string, lerr string, hard bool) error {
 	d.traceMsg("Entering tryCmd")
 	// Setup command
 	runCmd := exec.Command("bash", "-c", cmd)
+	d.lastCmd = cmd
 	d.cmdLogger.Printf("[godojo] # " + d.redactatron(cmd, d.redact) + "\n")
 
 	// Hook up stdout and strerr
@@ -54,14 +195,14 @@ func tryCmd(d *DDConfig, cmd string, lerr string, hard bool) error {
 	runCmd.Stderr = d.cmdLogger.Writer()
 
 	// Start the command
-	err := runCmd.Start()
+	err := d.startChild(runCmd)
 	if err != nil {
 		d.traceMsg(fmt.Sprintf("Failed to start command, error was: %+v", err))
 		return err
 	}
 
 	// Wait for command to exit, then check the exit code
-	err = runCmd.Wait()
+	err = d.waitChild(runCmd)
 	if err != nil {
 		// Check if the error is a ExitError
 		if exiterr, ok := err.(*exec.ExitError); ok {
@@ -104,6 +245,7 @@ func inspectCmd(d *DDConfig, cmd string, lerr string, hard bool) (string, error)
 	d.traceMsg("Inside inspectCmd")
 	// Setup command
 	runCmd := exec.Command("bash", "-c", cmd)
+	d.lastCmd = cmd
 	d.cmdLogger.Printf("[godojo] # " + d.redactatron(cmd, d.redact) + "\n")
 	//}
 
@@ -114,7 +256,7 @@ func inspectCmd(d *DDConfig, cmd string, lerr string, hard bool) (string, error)
 	runCmd.Stderr = d.cmdLogger.Writer()
 
 	// Start the command
-	err := runCmd.Start()
+	err := d.startChild(runCmd)
 	if err != nil {
 		d.traceMsg(fmt.Sprintf("%s - Failed to start command %+v, error was: %+v",
 			timeStamp(), d.redactatron(cmd, d.redact), err))
@@ -123,7 +265,7 @@ func inspectCmd(d *DDConfig, cmd string, lerr string, hard bool) (string, error)
 
 	d.traceMsg("Before runCmd.Wait()")
 	// Wait for command to exit, then check the exit code
-	err = runCmd.Wait()
+	err = d.waitChild(runCmd)
 	if err != nil {
 		// Check if the error is a ExitError
 		if exiterr, ok := err.(*exec.ExitError); ok {
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	c "github.com/mtesauro/commandeer"
+)
+
+func TestRunShellTimeout(t *testing.T) {
+	d := &DDConfig{shell: "sh"}
+	start := time.Now()
+	// The sleep is a child of the shell so the whole process group has to be killed
+	err := runShell(d, shellCmd(d, "sleep 10; echo done"), 200*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expecting a timeout error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Command wasn't killed at the timeout, ran for %s", time.Since(start))
+	}
+
+	if err := runShell(d, shellCmd(d, "true"), 0); err != nil {
+		t.Errorf("Expecting no error without a timeout, got %v", err)
+	}
+}
+
+func TestSignalStopsChildBeforeReleasingLocks(t *testing.T) {
+	if dir := os.Getenv("GODOJO_SIGNAL_TEST"); dir != "" {
+		signalledRun(dir)
+		return
+	}
+
+	dir := t.TempDir()
+	run := exec.Command(os.Args[0], "-test.run=^TestSignalStopsChildBeforeReleasingLocks$")
+	run.Env = append(os.Environ(), "GODOJO_SIGNAL_TEST="+dir)
+	if err := run.Start(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(filepath.Join(dir, "started")); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	// Only godojo gets the signal, like a CI runner aborting a job
+	_ = run.Process.Signal(syscall.SIGTERM)
+
+	var exitErr *exec.ExitError
+	if err := run.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != exitGeneral {
+		t.Fatalf("Expecting godojo to exit with %d, got %v", exitGeneral, err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(dir, "result")); string(b) != "held\n" {
+		t.Errorf("Expecting the lock to be held until the command exited, got %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, rootLockName)); err == nil {
+		t.Error("Expecting the lock to be released after the command exited")
+	}
+}
+
+// signalledRun takes a lock then runs a command that takes a while to exit
+// after SIGTERM, recording whether the lock is still held when it does
+func signalledRun(dir string) {
+	d := &DDConfig{shell: "sh", quiet: true, Error: log.New(io.Discard, "", 0), cmdLogger: log.New(io.Discard, "", 0)}
+	lock := filepath.Join(dir, rootLockName)
+	d.takeLock(lock)
+	script := fmt.Sprintf("trap 'sleep 0.5; [ -e %s ] && echo held > %s; exit 1' TERM; touch %s; sleep 30 & wait",
+		lock, filepath.Join(dir, "result"), filepath.Join(dir, "started"))
+	_ = runShell(d, shellCmd(d, script), 0)
+}
+
+func TestCmdEventsCarryPhase(t *testing.T) {
+	var out bytes.Buffer
+	d := &DDConfig{shell: "sh", quiet: true, events: &eventOut{w: &out},
+		Error: log.New(io.Discard, "", 0), cmdLogger: log.New(io.Discard, "", 0)}
+	// startdb commands also run inside the prepdb phase
+	d.inPhase("prepdb", func() {
+		runPhaseCmds(d, "startdb", "Starting database...", []c.SingleCmd{{Cmd: "true"}, {Cmd: "true"}})
+	})
+
+	cmds := 0
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var e event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Unable to decode event %s: %v", line, err)
+		}
+		if e.Phase != "prepdb" {
+			t.Errorf("Expecting phase prepdb, got %s in %s", e.Phase, line)
+		}
+		if e.Type == evCmdStart || e.Type == evCmdFinish {
+			cmds++
+		}
+	}
+	if cmds != 4 {
+		t.Errorf("Expecting 4 command events, got %d in %s", cmds, out.String())
+	}
+}
@@ -31,7 +31,7 @@ func writeDefaultConfig(c string, printNote bool) {
 	if err != nil {
 		fmt.Println("Unable to determine current working directory, exiting...")
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGeneral)
 	}
 
 	// Extract the embedded config file
@@ -40,7 +40,7 @@ func writeDefaultConfig(c string, printNote bool) {
 		// file was not found.
 		fmt.Println("Unable to extract embedded config file")
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGeneral)
 	}
 
 	// Write out the embedded default dojoConfig.yml
@@ -49,7 +49,7 @@ func writeDefaultConfig(c string, printNote bool) {
 		// Cannot write config file
 		fmt.Printf("Unable to write configuration file in %s, exiting...\n", path)
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitGeneral)
 	}
 
 	if printNote {
@@ -78,7 +78,7 @@ func readConfigFile(d *DDConfig) {
 		fmt.Println("")
 		fmt.Println("Unable to read the godojo config file (dojoConfig.yml), exiting install")
 		fmt.Printf("Error was: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitConfig)
 	}
 
 	// Marshall the config values into the DojoConfig struct
@@ -87,7 +87,7 @@ func readConfigFile(d *DDConfig) {
 		fmt.Println("")
 		fmt.Println("Unable to set the config values based on config file and ENV variables, exiting install")
 		fmt.Printf("Error was: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitConfig)
 	}
 }
 
@@ -98,7 +98,7 @@ func writeFinalConfig(d *DDConfig) {
 	err := viper.WriteConfigAs("runtime-install-config.yml")
 	if err != nil {
 		d.errorMsg(fmt.Sprintf("Error from writing the runtime config was: %+v", err))
-		os.Exit(1)
+		d.exitWith(exitGeneral)
 	}
 
 }
@@ -113,40 +113,46 @@ type dojoConfig struct {
 // InstallConfig - struct to hold the install time options
 type installConfig struct {
 	// Installer settings
-	Version       string         // Holds the version of Dojo to check out from the repo
-	SourceInstall bool           // If true, do a source install instead of a versioned release
-	SourceBranch  string         // Branch to checkout for a source install, if SourceCommit isn't "", SourceBranch will be ignored
-	SourceCommit  string         // head or full commit hash to install a specific commit, SourceBranch will be ignored if this isn't ""
-	Quiet         bool           // If true, suppress all output except for very early errors - logs will still be written in the log directory
-	Trace         bool           // If true, log at the trace level
-	Redact        bool           // If true, redact sensitive information from being logged.  Defaults to true
-	Prompt        bool           // Prompt at run time for install config.  If true, user will be prompted
-	Mac           bool           // The install set or type: Single Server, Dev, Stand-alone
-	Root          string         // Install root defaults to /opt/dojo
-	Source        string         // Directory to put the Dojo souce, child directory of Root
-	Files         string         // Directory for locally generated files like uploads, static, media, etc
-	App           string         // Directory where the Dojo Django app lives inside of Source above
-	Sampledata    bool           // Install the sample data if true, defaults to false
-	DB            dBTarget       // struct for DB configuration values
-	OS            oSTarget       // struct for DB configuration values
-	Settings      settingsTarget // struct for DB configuration values
-	Admin         adminTarget    // struct for DB configuration values
-	PullSource    bool           // If false, installer won't download source code - primarily for debugging
+	Version       string            // Holds the version of Dojo to check out from the repo
+	SourceInstall bool              // If true, do a source install instead of a versioned release
+	SourceBranch  string            // Branch to checkout for a source install, if SourceCommit isn't "", SourceBranch will be ignored
+	SourceCommit  string            // head or full commit hash to install a specific commit, SourceBranch will be ignored if this isn't ""
+	Quiet         bool              // If true, suppress all output except for very early errors - logs will still be written in the log directory
+	Trace         bool              // If true, log at the trace level
+	Redact        bool              // If true, redact sensitive information from being logged.  Defaults to true
+	Prompt        bool              // Prompt at run time for install config.  If true, user will be prompted
+	Mac           bool              // The install set or type: Single Server, Dev, Stand-alone
+	Root          string            // Install root defaults to /opt/dojo
+	Source        string            // Directory to put the Dojo souce, child directory of Root
+	Files         string            // Directory for locally generated files like uploads, static, media, etc
+	App           string            // Directory where the Dojo Django app lives inside of Source above
+	Sampledata    bool              // Install the sample data if true, defaults to false
+	DB            dBTarget          // struct for DB configuration values
+	OS            oSTarget          // struct for DB configuration values
+	Settings      settingsTarget    // struct for DB configuration values
+	Admin         adminTarget       // struct for DB configuration values
+	PullSource    bool              // If false, installer won't download source code - primarily for debugging
+	OSUpdates     string            // Upgrade OS packages while bootstrapping: none, security or all
+	Pins          map[string]string // Versions to install for OS packages, keyed by package name
 }
 
 // DBTarget - struct to hold Install.DB options
 type dBTarget struct {
-	Engine string
-	Local  bool
-	Exists bool
-	Ruser  string
-	Rpass  string
-	Name   string
-	User   string
-	Pass   string
-	Host   string
-	Port   int
-	Drop   bool
+	Engine    string
+	Local     bool
+	Exists    bool
+	Ruser     string
+	Rpass     string
+	Name      string
+	User      string
+	Pass      string
+	Host      string
+	Port      int
+	Drop      bool
+	Version   string // PostgreSQL major version, defaults to the distro's version
+	PGDG      bool   // Install PostgreSQL from the official PGDG repos
+	Wait      int    // Seconds to wait for a local database server to start, defaults to 120
+	BackupDir string // Where a database is backed up before Drop removes it, defaults to /var/backups/godojo
 }
 
 // OSTarget - struct to hold Install.OS options
@@ -272,15 +278,17 @@ type settingsConfig struct {
 // OptionalConfig values added to make developing and testing godojo easier
 // AKA you should never really need to change these.
 type optionalConfig struct {
-	HelpURL    string `yaml:"HelpURL"`
-	ReleaseURL string `yaml:"ReleaseURL"`
-	CloneURL   string `yaml:"CloneURL"`
-	YarnGPG    string `yaml:"YarnGPG"`
-	YarnRepo   string `yaml:"YarnRepo"`
-	NodeURL    string `yaml:"NodeURL"`
-	Embd       bool   `yaml:"Embd"`
-	Key        string `yaml:"Key"`
-	Tmpdir     string `yaml:"Tmpdir"`
-	UsrInst    bool   `yaml:"UsrInst"`
-	PyPath     string `yaml:"PyPath"`
+	HelpURL        string `yaml:"HelpURL"`
+	ReleaseURL     string `yaml:"ReleaseURL"`
+	CloneURL       string `yaml:"CloneURL"`
+	YarnGPG        string `yaml:"YarnGPG"`
+	YarnRepo       string `yaml:"YarnRepo"`
+	NodeURL        string `yaml:"NodeURL"`
+	Embd           bool   `yaml:"Embd"`
+	Key            string `yaml:"Key"`
+	Tmpdir         string `yaml:"Tmpdir"`
+	UsrInst        bool   `yaml:"UsrInst"`
+	PyPath         string `yaml:"PyPath"`
+	ClosestRelease bool   `yaml:"ClosestRelease"`
+	CmdPackDir     string `yaml:"CmdPackDir"`
 }
@@ -2,42 +2,158 @@ package cmd
 
 import (
 	"bufio"
+	"database/sql"
 	"errors"
 	"fmt"
 	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
-	"time"
+	"unicode"
 
-	"github.com/briandowns/spinner"
-	"github.com/defectdojo/godojo/distros"
-	c "github.com/mtesauro/commandeer"
+	"github.com/lib/pq"
 )
 
-// Setup a struct to use for DB commands
-type sqlStr struct {
-	os     string
-	sql    string
-	errMsg string
-	creds  map[string]string
-	kind   string
+// Database engines for Install.DB.Engine
+var dbEngines = map[string]bool{
+	"SQLite":     true,
+	"MySQL":      true,
+	"MariaDB":    true,
+	"PostgreSQL": true,
 }
 
 // saneDBConfig checks if the options configured in dojoConfig.yml are
 // possible aka sane and will exist the installer with a message if they are not
 func saneDBConfig(d *DDConfig) {
+	if !dbEngines[d.conf.Install.DB.Engine] {
+		d.errorMsg(fmt.Sprintf("Install.DB.Engine in dojoConfig.yml is %s, it must be SQLite, MySQL, MariaDB or PostgreSQL",
+			d.conf.Install.DB.Engine))
+		d.exitWith(exitConfig)
+	}
+
+	// PGDG and Version only apply to PostgreSQL
+	if d.conf.Install.DB.PGDG && d.conf.Install.DB.Engine != "PostgreSQL" {
+		d.errorMsg("Install.DB.PGDG in dojoConfig.yml is true but PGDG only provides PostgreSQL, set it to false for " +
+			d.conf.Install.DB.Engine)
+		d.exitWith(exitConfig)
+	}
+	if d.conf.Install.DB.Engine == "PostgreSQL" && d.conf.Install.DB.Version != "" {
+		if _, err := strconv.Atoi(d.conf.Install.DB.Version); err != nil {
+			d.errorMsg(fmt.Sprintf("Install.DB.Version in dojoConfig.yml is %s, it must be a PostgreSQL major version e.g. 16",
+				d.conf.Install.DB.Version))
+			d.exitWith(exitConfig)
+		}
+	}
+
+	// Names and passwords that can't be quoted safely everywhere they're used
+	problems := dbConfigProblems(d.conf.Install.DB, d.conf.Install.Admin.Pass)
+	if len(problems) > 0 {
+		for _, p := range problems {
+			d.errorMsg(p)
+		}
+		d.exitWith(exitConfig)
+	}
+
+	// SQLite is a file on this host so there's no server to install or reach
+	if d.conf.Install.DB.Engine == "SQLite" {
+		sqliteWarning(d)
+		d.conf.Install.DB.Local = true
+		return
+	}
+
 	// Remote database that doesn't exist - godojo can't help you here
 	if !d.conf.Install.DB.Local && !d.conf.Install.DB.Exists {
 		d.errorMsg("Remote database which doens't exist was confgiured in dojoConfig.yml.")
 		d.errorMsg("This is an unsupported configuration.")
 		d.statusMsg("Correct configuration and/or install a remote DB before running installer again.")
 		fmt.Printf("Exiting...\n\n")
-		os.Exit(1)
+		d.exitWith(exitConfig)
+	}
+}
+
+// saneDBForTarget checks Install.DB.PGDG and Install.DB.Version against the
+// install target before anything is installed
+func saneDBForTarget(d *DDConfig, t *targetOS) {
+	if d.conf.Install.DB.Engine != "PostgreSQL" {
+		return
+	}
+	dist := targetDistro(d, t)
+	if d.conf.Install.DB.PGDG {
+		cmds, err := dist.DBCommands("installdb", t.id, "PostgreSQL-PGDG")
+		if err != nil || len(cmds) == 0 {
+			d.errorMsg(fmt.Sprintf("Install.DB.PGDG in dojoConfig.yml is true but godojo can't install PostgreSQL from the PGDG repos on %s, set it to false",
+				t.id))
+			d.exitWith(exitConfig)
+		}
+		return
+	}
+
+	// Debian and Ubuntu only package one PostgreSQL version per release
+	v, def := d.conf.Install.DB.Version, dist.DBVersion(t.id, "PostgreSQL")
+	if (t.distro == "ubuntu" || t.distro == "debian") && v != "" && def != "" && v != def {
+		d.errorMsg(fmt.Sprintf("Install.DB.Version in dojoConfig.yml is %s but %s only packages PostgreSQL %s, "+
+			"set PGDG to true to install PostgreSQL %s from the PGDG repos or remove Version", v, t.id, def, v))
+		d.exitWith(exitConfig)
 	}
 }
 
+// Database and user names godojo will create, these are quoted in SQL but
+// also end up in file names and DefectDojo's settings
+var dbNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dbConfigProblems returns a message for each Install.DB value, or the
+// admin password, that godojo can't use safely
+func dbConfigProblems(db dBTarget, adminPass string) []string {
+	problems := []string{}
+	names := []struct {
+		key string
+		val string
+		max int
+	}{
+		{"Install.DB.Name", db.Name, 63}, // PostgreSQL's limit, MySQL allows 64
+		{"Install.DB.User", db.User, 32}, // MySQL's limit, PostgreSQL allows 63
+	}
+	if db.Engine == "SQLite" {
+		// Only the name is used, for the database file
+		names = names[:1]
+	}
+	for _, n := range names {
+		if !dbNameRE.MatchString(n.val) || len(n.val) > n.max {
+			problems = append(problems, fmt.Sprintf("%s in dojoConfig.yml is '%s', it must be 1 to %d letters, numbers "+
+				"or underscores and not start with a number", n.key, n.val, n.max))
+		}
+	}
+
+	// Passwords are quoted in SQL and percent-encoded in DD_DATABASE_URL, but
+	// control characters like newlines break .env.prod and the expect script
+	passwords := []struct {
+		key string
+		val string
+	}{
+		{"Install.DB.Pass", db.Pass},
+		{"Install.DB.Rpass", db.Rpass},
+		{"Install.Admin.Pass", adminPass},
+	}
+	for _, p := range passwords {
+		if strings.IndexFunc(p.val, unicode.IsControl) >= 0 {
+			problems = append(problems, fmt.Sprintf("%s in dojoConfig.yml contains a control character like a newline or tab, "+
+				"which isn't supported", p.key))
+		}
+	}
+
+	return problems
+}
+
 // prepDBForDojo
 func installDBForDojo(d *DDConfig, t *targetOS) {
+	// SQLite has no server or client packages to install
+	if d.conf.Install.DB.Engine == "SQLite" {
+		d.statusMsg("SQLite doesn't need a database server, skipping database install")
+		return
+	}
+
 	// Handle the case that the DB is local and doesn't exist
 	if !d.conf.Install.DB.Exists {
 		// Note that godojo won't try to install remote databases
@@ -63,58 +179,13 @@ func dbNotExist(d *DDConfig, t *targetOS) {
 	// Handle the case that the DB is local and doesn't exist
 	d.sectionMsg("Installing database needed for DefectDojo")
 
-	// Create a new install DB command package
-	cInstallDB := c.NewPkg("installdb")
-
-	// Get commands for the right distro & DB
-	switch {
-	case t.distro == "ubuntu":
-		d.traceMsg("DB needs to be installed on Ubuntu")
-		err := distros.GetUbuntuDB(cInstallDB, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to install DB on target OS %s was\n", t.id)
-			fmt.Printf("\t%+v\n", err)
-			os.Exit(1)
-		}
-		if strings.ToLower(d.conf.Install.DB.Engine) == "mysql" {
-			d.warnMsg("WARNING: While supported, there is significantly more testing with PostreSQL than MySQL. YMMV.")
-		}
-	case t.distro == "rhel":
-		d.traceMsg("DB needs to be installed on RHEL")
-		err := distros.GetRHELDB(cInstallDB, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to install DB on target OS %s was\n", t.id)
-			fmt.Printf("\t%+v\n", err)
-			os.Exit(1)
-		}
-		if strings.ToLower(d.conf.Install.DB.Engine) == "mysql" {
-			d.warnMsg("WARNING: While supported, there is significantly more testing with PostreSQL than MySQL. YMMV.")
-		}
-	default:
-		d.traceMsg(fmt.Sprintf("Distro identified (%s) is not supported", t.id))
-		fmt.Printf("Distro identified by godojo (%s) is not supported, exiting...\n", t.id)
-		os.Exit(1)
-	}
-
-	// Run the commands to install the chosen DB
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Installing " + d.conf.Install.DB.Engine + " database for DefectDojo..."
-	d.spin.Start()
-	// Run the install DB for the target OS
-	tCmds, err := distros.CmdsForTarget(cInstallDB, t.id)
-	if err != nil {
-		fmt.Printf("Error getting commands to install DB target OS %s\n", t.id)
-		os.Exit(1)
+	// Get the installdb commands for the target OS
+	tCmds := distroDBCmds(d, t, "installdb")
+	if d.conf.Install.DB.Engine == "MySQL" || d.conf.Install.DB.Engine == "MariaDB" {
+		d.warnMsg("WARNING: While supported, there is significantly more testing with PostreSQL than " + d.conf.Install.DB.Engine + ". YMMV.")
 	}
 
-	for i := range tCmds {
-		sendCmd(d,
-			d.cmdLogger,
-			tCmds[i].Cmd,
-			tCmds[i].Errmsg,
-			tCmds[i].Hard)
-	}
-	d.spin.Stop()
+	runPhaseCmds(d, "installdb", "Installing "+d.conf.Install.DB.Engine+" database for DefectDojo...", tCmds)
 	d.statusMsg("Installing Database complete")
 }
 
@@ -123,52 +194,10 @@ func dbClient(d *DDConfig, t *targetOS) {
 	// Handle the case that the DB is local and doesn't exist
 	d.sectionMsg("Installing database client needed for DefectDojo")
 
-	// Create a new install DB client command package
-	cInstallDBClient := c.NewPkg("installdbclient")
+	// Get the installdbclient commands for the target OS
+	tCmds := distroDBCmds(d, t, "installdbclient")
 
-	// Get the commands for the right distro & DB
-	switch {
-	case t.distro == "ubuntu":
-		d.traceMsg("DB client needs to be installed on Ubuntu")
-		err := distros.GetUbuntuDB(cInstallDBClient, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to install DB client on target OS %s was\n", t.id)
-			fmt.Printf("\t%+v\n", err)
-			os.Exit(1)
-		}
-	case t.distro == "rhel":
-		d.traceMsg("DB client needs to be installed on RHEL")
-		err := distros.GetRHELDB(cInstallDBClient, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to install DB client on target OS %s was\n", t.id)
-			fmt.Printf("\t%+v\n", err)
-			os.Exit(1)
-		}
-	default:
-		d.traceMsg(fmt.Sprintf("Distro identified (%s) is not supported", t.id))
-		fmt.Printf("Distro identified by godojo (%s) is not supported, exiting...\n", t.id)
-		os.Exit(1)
-	}
-
-	// Run the commands to install the chosen DB
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Installing " + d.conf.Install.DB.Engine + " database client for DefectDojo..."
-	d.spin.Start()
-	// Run the install DB client for the target OS
-	tCmds, err := distros.CmdsForTarget(cInstallDBClient, t.id)
-	if err != nil {
-		fmt.Printf("Error getting commands to install DB target OS %s\n", t.id)
-		os.Exit(1)
-	}
-
-	for i := range tCmds {
-		sendCmd(d,
-			d.cmdLogger,
-			tCmds[i].Cmd,
-			tCmds[i].Errmsg,
-			tCmds[i].Hard)
-	}
-	d.spin.Stop()
+	runPhaseCmds(d, "installdbclient", "Installing "+d.conf.Install.DB.Engine+" database client for DefectDojo...", tCmds)
 	d.statusMsg("Installing Database client complete")
 
 }
@@ -178,50 +207,17 @@ func localDBStart(d *DDConfig, t *targetOS) {
 	// Handle the case that the DB is local and doesn't exist
 	d.sectionMsg("Starting the database needed for DefectDojo")
 
-	// Create new boostrap command package
-	cStartDB := c.NewPkg("startdb")
+	// Get the startdb commands for the target OS
+	tCmds := distroDBCmds(d, t, "startdb")
 
-	// Get commands for the right distro
-	switch {
-	case t.distro == "ubuntu":
-		d.traceMsg("Searching for commands to start MySQL under Ubuntu")
-		err := distros.GetUbuntuDB(cStartDB, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to start database under target OS %s\n", t.id)
-			os.Exit(1)
-		}
-	case t.distro == "rhel":
-		d.traceMsg("Searching for commands to start MySQL under RHEL")
-		err := distros.GetRHELDB(cStartDB, t.id, d.conf.Install.DB.Engine)
-		if err != nil {
-			fmt.Printf("Error searching for commands to start database under target OS %s\n", t.id)
-			os.Exit(1)
-		}
-	default:
-		d.traceMsg(fmt.Sprintf("Distro identified (%s) is not supported", t.id))
-		fmt.Printf("Distro identified by godojo (%s) is not supported, exiting...\n", t.id)
-		os.Exit(1)
-	}
+	runPhaseCmds(d, "startdb", "Starting "+d.conf.Install.DB.Engine+" database for DefectDojo...", tCmds)
 
-	// Run the commands to install the chosen DB
-	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
-	d.spin.Prefix = "Starting " + d.conf.Install.DB.Engine + " database for DefectDojo..."
-	d.spin.Start()
-	// Run the start DB command(s) for the target OS
-	tCmds, err := distros.CmdsForTarget(cStartDB, t.id)
+	// The start commands can return before the server accepts connections
+	err := waitForDB(d, t)
 	if err != nil {
-		fmt.Printf("Error getting commands to start DB on target OS %s\n", t.id)
-		os.Exit(1)
-	}
-
-	for i := range tCmds {
-		sendCmd(d,
-			d.cmdLogger,
-			tCmds[i].Cmd,
-			tCmds[i].Errmsg,
-			tCmds[i].Hard)
+		d.errorMsg(fmt.Sprintf("%+v", err))
+		d.exitWith(exitDBConnect)
 	}
-	d.spin.Stop()
 	d.statusMsg("Starting Database complete")
 }
 
@@ -237,11 +233,14 @@ func prepDBForDojo(d *DDConfig, t *targetOS) {
 	err := dbPrep(d, t)
 	if err != nil {
 		d.errorMsg(fmt.Sprintf("%+v", err))
-		os.Exit(1)
+		if errors.Is(err, errDBUnreachable) {
+			d.exitWith(exitDBConnect)
+		}
+		d.exitWith(exitDBPrep)
 	}
 
 	// Start the installed DB
-	if d.conf.Install.DB.Local {
+	if d.conf.Install.DB.Local && d.conf.Install.DB.Engine != "SQLite" {
 		d.traceMsg("Starting the local DB")
 		localDBStart(d, t)
 	}
@@ -252,8 +251,10 @@ func prepDBForDojo(d *DDConfig, t *targetOS) {
 func dbPrep(d *DDConfig, t *targetOS) error {
 	// Call the necessary function for the supported DB engines
 	switch d.conf.Install.DB.Engine {
-	case "MySQL":
-		// Generate commands to install MySQL
+	case "SQLite":
+		return prepSQLite(d)
+	case "MySQL", "MariaDB":
+		// MariaDB uses the same client and SQL as MySQL
 		return prepMySQL(d, t.id)
 	case "PostgreSQL":
 		// Generate commands to install PostgreSQL
@@ -263,11 +264,83 @@ func dbPrep(d *DDConfig, t *targetOS) error {
 	return errors.New("Unknown database engine configured, cannot check connectivity")
 }
 
-func prepMySQL(d *DDConfig, osTar string) error {
-	// TODO: Path check any binaries called
-	//       * mysql
-	// TODO: Check MySQL version and handle MySQL 8 and password format change
+// sqliteWarning tells the user loudly that SQLite is for evaluation only
+func sqliteWarning(d *DDConfig) {
+	d.warnMsg("WARNING: SQLite is for quick evaluation installs ONLY - DO NOT use it for production.")
+	d.warnMsg("WARNING: It doesn't handle concurrent writes from DefectDojo's workers and there's no upgrade path")
+	d.warnMsg("WARNING: from SQLite to MySQL, MariaDB or PostgreSQL. Expect to throw this install away.")
+}
+
+// sqlitePath returns the SQLite database file, kept in Install.Files with
+// DefectDojo's other local files
+func sqlitePath(d *DDConfig) string {
+	return filepath.Join(d.conf.Install.Root, d.conf.Install.Files, d.conf.Install.DB.Name+".sqlite3")
+}
+
+// prepSQLite creates the SQLite database file for DefectDojo, removing an
+// existing one if Drop is true.  Django creates the tables during migrations.
+func prepSQLite(d *DDConfig) error {
+	sqliteWarning(d)
+	dbFile := sqlitePath(d)
+	d.traceMsg(fmt.Sprintf("SQLite database file is %s", dbFile))
+
+	_, err := os.Stat(dbFile)
+	if err == nil {
+		if !d.conf.Install.DB.Drop {
+			return fmt.Errorf("SQLite database %s already exists and Drop is false in dojoConfig.yml", dbFile)
+		}
+		err = backupBeforeDrop(d, nil, nil)
+		if err != nil {
+			return err
+		}
+		d.traceMsg("Drop is true, removing the existing SQLite database")
+		err = os.Remove(dbFile)
+		if err != nil {
+			return fmt.Errorf("Unable to remove existing SQLite database %s: %w", dbFile, err)
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(dbFile), 0755)
+	if err != nil {
+		return fmt.Errorf("Unable to create directory for SQLite database: %w", err)
+	}
+	f, err := os.OpenFile(dbFile, os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("Unable to create SQLite database %s: %w", dbFile, err)
+	}
+	f.Close()
+
+	// prepdjango creates the OS user then chowns Install.Root which includes
+	// the database, so only set ownership here if the user already exists
+	return chownDojo(d, filepath.Dir(dbFile), dbFile)
+}
+
+// chownDojo sets the owner of paths to the DefectDojo OS user and group if
+// they exist
+func chownDojo(d *DDConfig, paths ...string) error {
+	u, err := user.Lookup(d.conf.Install.OS.User)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("OS user %s doesn't exist yet, leaving ownership for prepdjango", d.conf.Install.OS.User))
+		return nil
+	}
+	g, err := user.LookupGroup(d.conf.Install.OS.Group)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("OS group %s doesn't exist yet, leaving ownership for prepdjango", d.conf.Install.OS.Group))
+		return nil
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(g.Gid)
+	for _, p := range paths {
+		err = os.Chown(p, uid, gid)
+		if err != nil {
+			return fmt.Errorf("Unable to set ownership of %s: %w", p, err)
+		}
+	}
+
+	return nil
+}
 
+func prepMySQL(d *DDConfig, osTar string) error {
 	// Set Creds based on dojoConfig.yml
 	creds := map[string]string{"user": d.conf.Install.DB.Ruser, "pass": d.conf.Install.DB.Rpass}
 	d.traceMsg(fmt.Sprintf("DB Creds from config are %s / %s", creds["user"], creds["pass"]))
@@ -283,63 +356,52 @@ func prepMySQL(d *DDConfig, osTar string) error {
 
 	d.statusMsg("Validating DB connection")
 	// Check connectivity to DB
-	conCk := sqlStr{
-		os:     osTar,
-		sql:    "SHOW PROCESSLIST;",
-		errMsg: "Unable to connect to the configured MySQL database",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err := runMySQLCmd(d, conCk)
+	db, err := dbOpen(d, creds)
 	if err != nil {
 		d.traceMsg("validation of connection to MySQL failed")
 		return err
 	}
+	defer db.Close()
 
-	// Drop existing DefectDojo database if it exists and configuration says to
-	if d.conf.Install.DB.Drop {
-		d.traceMsg("Dropping any existing database per Install.DB.Drop=True in dojoConfig.yml")
-		// Query MySQL to see if the configured database name exists already
-		// Another option is "show databases like '" + d.conf.Install.DB.Name + "';"
-		dbCk := sqlStr{
-			os: osTar,
-			sql: "SELECT count(SCHEMA_NAME) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = '" +
-				d.conf.Install.DB.Name + "';",
-			errMsg: "Unable to check for existing DefectDojo MySQL database",
-			creds:  creds,
-			kind:   "inspect",
-		}
-		out, err := runMySQLCmd(d, dbCk)
+	// Check the server is the configured engine and new enough for DefectDojo
+	err = checkMySQLVersion(d, db)
+	if err != nil {
+		return err
+	}
+
+	// Give root on a fresh MariaDB install, including RHEL's for MySQL, the configured password
+	rhel := strings.HasPrefix(strings.ToLower(osTar), "rhel:")
+	if d.conf.Install.DB.Local && !d.conf.Install.DB.Exists && (d.conf.Install.DB.Engine == "MariaDB" || rhel) {
+		err = setMySQLRootPass(d, db)
 		if err != nil {
-			d.traceMsg("Check for existing DefectDojo MySQL database failed")
-			fmt.Println("Drop database set to true but no database found, continuing")
-			//return err
+			return err
 		}
+	}
 
-		// Clean up stdout from inspectCmd output
-		strOut := squishSlice(out)
-		resp := strings.Trim(
-			strings.ReplaceAll(
-				strings.ReplaceAll(strOut, "count(SCHEMA_NAME)", ""), "\n", ""), " ")
+	// Check the remote server will let Ruser create DefectDojo's database and user
+	if !d.conf.Install.DB.Local {
+		err = checkMySQLPrivs(d, db, creds["user"])
+		if err != nil {
+			return err
+		}
+	}
 
-		// Check if there's an existing DB
-		// if resp = 0 then DB doesn't exist
-		// if resp = 1 then the DB exists already and needs to be dropped first
-		ck, err := strconv.Atoi(resp)
+	// Drop existing DefectDojo database if it exists and configuration says to
+	if d.conf.Install.DB.Drop {
+		d.traceMsg("Dropping any existing database per Install.DB.Drop=True in dojoConfig.yml")
+		n, err := dbCount(d, db, "Unable to check for existing DefectDojo MySQL database",
+			"SELECT COUNT(*) FROM INFORMATION_SCHEMA.SCHEMATA WHERE SCHEMA_NAME = ?", d.conf.Install.DB.Name)
 		if err != nil {
-			d.traceMsg("Unable to convert existing DB check string to int")
 			return err
 		}
-		if ck == 1 {
-			d.traceMsg("DB EXISTS so droping that sucker")
-			dropDB := sqlStr{
-				os:     osTar,
-				sql:    "DROP DATABASE " + d.conf.Install.DB.Name + ";",
-				errMsg: "Unable to drop the existing MySQL database",
-				creds:  creds,
-				kind:   "try",
+		if n > 0 {
+			err = backupBeforeDrop(d, db, creds)
+			if err != nil {
+				return err
 			}
-			_, err := runMySQLCmd(d, dropDB)
+			d.traceMsg("DB EXISTS so droping that sucker")
+			err = dbExec(d, db, "Unable to drop the existing MySQL database",
+				"DROP DATABASE "+mysqlIdent(d.conf.Install.DB.Name))
 			if err != nil {
 				d.traceMsg("Failed to drop existing database per configured option to drop existing")
 				return err
@@ -347,19 +409,12 @@ func prepMySQL(d *DDConfig, osTar string) error {
 			fmt.Printf("Existing database %+v dropped since Database Drop was set to %+v\n",
 				d.conf.Install.DB.Name, d.conf.Install.DB.Drop)
 		}
-
 	}
 
 	// Create the DefectDojo database if it doesn't already exist
 	d.traceMsg("Creating database for DefectDojo on MySQL")
-	createDB := sqlStr{
-		os:     osTar,
-		sql:    "CREATE DATABASE IF NOT EXISTS " + d.conf.Install.DB.Name + "  CHARACTER SET UTF8;",
-		errMsg: "Unable to create a new MySQL database for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runMySQLCmd(d, createDB)
+	err = dbExec(d, db, "Unable to create a new MySQL database for DefectDojo",
+		"CREATE DATABASE IF NOT EXISTS "+mysqlIdent(d.conf.Install.DB.Name)+" CHARACTER SET UTF8")
 	if err != nil {
 		d.traceMsg("Failed to create new database for DefectDojo to use")
 		return err
@@ -367,21 +422,10 @@ func prepMySQL(d *DDConfig, osTar string) error {
 
 	// Drop user DefectDojo uses to connect to the database
 	d.traceMsg("Dropping existing DefectDojo MySQL DB user, if any")
-	dropUsr := sqlStr{
-		os: osTar,
-		sql: "DROP USER '" + d.conf.Install.DB.User + "'@'localhost';DROP USER '" +
-			d.conf.Install.DB.User + "'@'%';",
-		errMsg: "Unable to delete existing database user for DefectDojo or one didn't exist",
-		creds:  creds,
-		kind:   "inspect",
-	}
-	out, err := runMySQLCmd(d, dropUsr)
+	err = dbExec(d, db, "Unable to delete existing database user for DefectDojo",
+		"DROP USER IF EXISTS ?@'localhost', ?@'%'", d.conf.Install.DB.User, d.conf.Install.DB.User)
 	if err != nil {
-		// No reason to return the error as this is expected for most cases
-		// and create user will error out for edge cases
-		d.traceMsg("Unable to delete existing database user for DefectDojo or one didn't exist")
-		d.traceMsg(fmt.Sprintf("SQL DROP command output was %+v (in any)", squishSlice(out)))
-		d.traceMsg("Continuing after error deleting user, non-fatal error")
+		return err
 	}
 
 	// First set the appropriate host for the DefectDojo user to connect from
@@ -392,15 +436,8 @@ func prepMySQL(d *DDConfig, osTar string) error {
 	}
 	// Create user for DefectDojo to use to connect to the database
 	d.traceMsg("Creating MySQL DB user for DefectDojo")
-	createUsr := sqlStr{
-		os: osTar,
-		sql: "CREATE USER '" + d.conf.Install.DB.User + "'@'" + usrHost +
-			"' IDENTIFIED BY '" + d.conf.Install.DB.Pass + "';",
-		errMsg: "Unable to create a MySQL database user for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runMySQLCmd(d, createUsr)
+	err = dbExec(d, db, "Unable to create a MySQL database user for DefectDojo",
+		"CREATE USER ?@? IDENTIFIED BY ?", d.conf.Install.DB.User, usrHost, d.conf.Install.DB.Pass)
 	if err != nil {
 		d.traceMsg("Failed to create database user for DefectDojo")
 		return err
@@ -408,15 +445,8 @@ func prepMySQL(d *DDConfig, osTar string) error {
 
 	// Grant the DefectDojo db user the necessary privileges
 	d.traceMsg("Granting privileges to DefectDojo MySQL DB user")
-	grantPrivs := sqlStr{
-		os: osTar,
-		sql: "GRANT ALL PRIVILEGES ON " + d.conf.Install.DB.Name + ".* TO '" +
-			d.conf.Install.DB.User + "'@'" + d.conf.Install.DB.Host + "';",
-		errMsg: "Unable to grant needed privileges to database user for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runMySQLCmd(d, grantPrivs)
+	err = dbExec(d, db, "Unable to grant needed privileges to database user for DefectDojo",
+		"GRANT ALL PRIVILEGES ON "+mysqlIdent(d.conf.Install.DB.Name)+".* TO ?@?", d.conf.Install.DB.User, usrHost)
 	if err != nil {
 		d.traceMsg("Failed to grant privileges to database user for DefectDojo")
 		return err
@@ -424,16 +454,8 @@ func prepMySQL(d *DDConfig, osTar string) error {
 
 	// Flush privileges to finalize changes to db
 	d.traceMsg("Flushing privileges for DefectDojo MySQL DB user")
-	flushPrivs := sqlStr{
-		os:     osTar,
-		sql:    "FLUSH PRIVILEGES;",
-		errMsg: "Unable to flush database privileges",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runMySQLCmd(d, flushPrivs)
+	err = dbExec(d, db, "Unable to flush database privileges", "FLUSH PRIVILEGES")
 	if err != nil {
-		d.traceMsg("Failed to create database user for DefectDojo")
 		return err
 	}
 
@@ -448,56 +470,173 @@ func prepMySQL(d *DDConfig, osTar string) error {
 	return nil
 }
 
-func runMySQLCmd(d *DDConfig, c sqlStr) ([]string, error) {
-	out := make([]string, 1)
-	d.traceMsg(fmt.Sprintf("MySQL query: %s", c.sql))
-	DBCmds := osCmds{
-		id: c.os,
-		cmds: []string{"mysql --host=" + d.conf.Install.DB.Host +
-			" --user=" + c.creds["user"] +
-			" --port=" + strconv.Itoa(d.conf.Install.DB.Port) +
-			" --password=" + c.creds["pass"] +
-			" --execute=\"" + c.sql + "\""},
-		errmsg: []string{c.errMsg},
-		hard:   []bool{false},
-	}
-
-	// Switch on how to run the command aka runCmds, tryCmds, inspectCmds
-	switch c.kind {
-	case "try":
-		err := tryCmds(d, DBCmds)
+// checkMySQLVersion checks that a MariaDB engine is really MariaDB and that
+// the server version is supported by DefectDojo's Django release
+func checkMySQLVersion(d *DDConfig, db *sql.DB) error {
+	// Versions look like 10.5.22-MariaDB or 8.0.36-0ubuntu0.22.04.1
+	ver := ""
+	err := db.QueryRow("SELECT VERSION()").Scan(&ver)
+	if err != nil {
+		return fmt.Errorf("Unable to get the version of the %s server: %w", d.conf.Install.DB.Engine, err)
+	}
+	d.traceMsg(fmt.Sprintf("Database server version is %s", ver))
+	mariaDB := strings.Contains(ver, "MariaDB")
+	if d.conf.Install.DB.Engine == "MariaDB" && !mariaDB {
+		return fmt.Errorf("Install.DB.Engine is MariaDB but the server on %s is MySQL %s, set Install.DB.Engine to MySQL",
+			d.conf.Install.DB.Host, ver)
+	}
+
+	name, min := "MySQL", "8.0.11"
+	if mariaDB {
+		name, min = "MariaDB", "10.4"
+	}
+	if !versionAtLeast(ver, min) {
+		return fmt.Errorf("%s %s on %s is too old for DefectDojo, %s %s or newer is needed",
+			name, ver, d.conf.Install.DB.Host, name, min)
+	}
+
+	return nil
+}
+
+// versionAtLeast returns true if a server version like 10.5.22-MariaDB is
+// the same or newer than min e.g. 10.4
+func versionAtLeast(ver string, min string) bool {
+	num, _, _ := strings.Cut(ver, "-")
+	have := strings.Split(num, ".")
+	for i, m := range strings.Split(min, ".") {
+		if i >= len(have) {
+			return false
+		}
+		h, err := strconv.Atoi(have[i])
 		if err != nil {
-			d.traceMsg(fmt.Sprintf("Error running MySQL command - %s", c.sql))
-			return out, err
+			return false
+		}
+		w, _ := strconv.Atoi(m)
+		if h != w {
+			return h > w
 		}
-	case "inspect":
-		out, err := inspectCmds(d, DBCmds)
+	}
+
+	return true
+}
+
+// setMySQLRootPass sets root's password to Install.DB.Rpass on a fresh
+// MariaDB install.  root keeps unix_socket auth so godojo and local admins can
+// still connect over the socket as the OS root user.
+func setMySQLRootPass(d *DDConfig, db *sql.DB) error {
+	if d.conf.Install.DB.Rpass == "" {
+		d.traceMsg("Install.DB.Rpass is empty, leaving MariaDB root with only unix_socket auth")
+		return nil
+	}
+
+	d.traceMsg("Setting the MariaDB root password with unix_socket auth")
+	err := dbExec(d, db, "Unable to set the password for the MariaDB root user",
+		"ALTER USER 'root'@'localhost' IDENTIFIED VIA unix_socket OR mysql_native_password USING PASSWORD(?)",
+		d.conf.Install.DB.Rpass)
+	if err != nil {
+		d.traceMsg("Failed to set the MariaDB root password")
+		return err
+	}
+
+	return nil
+}
+
+// checkMySQLPrivs checks that the admin user configured in Install.DB.Ruser
+// has the privileges needed to create DefectDojo's database and user on a
+// remote MySQL server before any changes are made to it
+func checkMySQLPrivs(d *DDConfig, db *sql.DB, user string) error {
+	d.statusMsg(fmt.Sprintf("Validating privileges of %s on the remote MySQL server", user))
+	rows, err := db.Query("SHOW GRANTS FOR CURRENT_USER()")
+	if err != nil {
+		return fmt.Errorf("Unable to list the privileges of the configured MySQL admin user: %w", err)
+	}
+	defer rows.Close()
+	grants := []string{}
+	for rows.Next() {
+		g := ""
+		err = rows.Scan(&g)
 		if err != nil {
-			d.traceMsg(fmt.Sprintf("Error running MySQL command - %s", c.sql))
-			return out, err
+			return fmt.Errorf("Unable to read the privileges of the configured MySQL admin user: %w", err)
 		}
-	default:
-		d.traceMsg("Invalid 'kind' sent to runMySQLCmd, bug in godojo")
-		fmt.Println("Bug discovered in godojo, see trace message or re-run with trace logging. Quitting.")
-		os.Exit(1)
+		grants = append(grants, g)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("Unable to list the privileges of the configured MySQL admin user: %w", err)
+	}
+
+	missing, roles := missingMySQLPrivs(grants, d.conf.Install.DB.Name, d.conf.Install.DB.Drop)
+	if len(missing) > 0 && roles {
+		// SHOW GRANTS doesn't expand roles so they may have the missing privileges
+		d.warnMsg(fmt.Sprintf("MySQL user %s on %s doesn't have the %s privilege(s) directly but has roles which may grant them, continuing",
+			user, d.conf.Install.DB.Host, strings.Join(missing, ", ")))
+		return nil
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("MySQL user %s on %s is missing the %s privilege(s) needed to create the DefectDojo database and user",
+			user, d.conf.Install.DB.Host, strings.Join(missing, ", "))
 	}
+	d.traceMsg("MySQL admin user has the needed privileges")
 
-	return out, nil
+	return nil
 }
 
-// squishSlice
-func squishSlice(sl []string) string {
-	str := ""
-	for i := 0; i < len(sl); i++ {
-		str += sl[i]
+// missingMySQLPrivs takes the output of SHOW GRANTS and returns the
+// privileges needed to create the database db and its user that weren't
+// granted, including DROP if the existing database will be dropped, and true
+// if the user was granted roles which SHOW GRANTS doesn't expand
+func missingMySQLPrivs(grants []string, db string, drop bool) ([]string, bool) {
+	have := make(map[string]bool)
+	roles := false
+	for _, g := range grants {
+		// Grants look like GRANT CREATE, DROP ON `dojo`.* TO `admin`@`%` WITH GRANT OPTION
+		// and roles like GRANT `dba`@`%` TO `admin`@`%`
+		g = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(g), "\\", ""))
+		if !strings.HasPrefix(g, "GRANT ") {
+			continue
+		}
+		on := strings.Index(g, " ON ")
+		if on < 0 {
+			roles = roles || strings.Contains(g, " TO ")
+			continue
+		}
+		scope := strings.Fields(g[on+4:])[0]
+		global := scope == "*.*"
+		if !global && strings.Trim(strings.TrimSuffix(scope, ".*"), "`") != strings.ToUpper(db) {
+			continue
+		}
+
+		for _, p := range strings.Split(g[len("GRANT "):on], ",") {
+			p = strings.TrimSpace(p)
+			switch {
+			case p == "ALL PRIVILEGES" || p == "ALL":
+				have["CREATE"], have["DROP"] = true, true
+				have["CREATE USER"] = have["CREATE USER"] || global
+			case p == "CREATE USER" && !global:
+				// CREATE USER is only meaningful globally
+			default:
+				have[p] = true
+			}
+		}
+		if strings.Contains(g, "WITH GRANT OPTION") {
+			have["GRANT OPTION"] = true
+		}
+	}
+
+	need := []string{"CREATE", "CREATE USER", "GRANT OPTION"}
+	if drop {
+		need = append(need, "DROP")
 	}
-	return str
+	missing := []string{}
+	for _, p := range need {
+		if !have[p] {
+			missing = append(missing, p)
+		}
+	}
+
+	return missing, roles
 }
 
 func prepPostgreSQL(d *DDConfig, t *targetOS) error {
-	// TODO: Path check any binaries called
-	//       * postgres
-
 	// Set Creds based on dojoConfig.yml
 	creds := map[string]string{"user": d.conf.Install.DB.Ruser, "pass": d.conf.Install.DB.Rpass}
 	d.traceMsg(fmt.Sprintf("DB Creds from config are %s / %s", creds["user"], creds["pass"]))
@@ -517,121 +656,72 @@ func prepPostgreSQL(d *DDConfig, t *targetOS) error {
 		return errors.New("Unable to update pg_hba.conf so SQL to the DB will fail.  Exiting")
 	}
 
-	// Use pg_isready to check connectivity to PostgreSQL DB
+	// Check connectivity to the PostgreSQL DB
 	d.statusMsg("Checking connectivity to PostgreSQL")
-
-	readyOut, err := isPgReady(d, creds)
+	db, err := dbOpen(d, creds)
 	if err != nil {
 		d.traceMsg(fmt.Sprintf("PostgreSQL is not available, error was %+v", err))
 		return err
 	}
-	d.traceMsg(fmt.Sprintf("Output from pgReady: %+v", readyOut))
+	defer db.Close()
 
-	d.statusMsg("Validating DB connection settings")
-	// Check connectivity to DB
-	conCk := sqlStr{
-		os:     t.id,
-		sql:    "\\l",
-		errMsg: "Unable to connect to the configured Postgres database",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runPgSQLCmd(d, conCk)
+	// Drop existing DefectDojo database if it exists and configuration says to
+	name := pq.QuoteIdentifier(d.conf.Install.DB.Name)
+	exists, err := dbCount(d, db, "Unable to check for existing DefectDojo PostgreSQL database",
+		"SELECT COUNT(*) FROM pg_database WHERE datname = $1", d.conf.Install.DB.Name)
 	if err != nil {
-		d.traceMsg("validation of connection to Postgres failed")
-		// TODO Fix this validation bypass
-		//return err
+		return err
 	}
-
-	// Drop existing DefectDojo database if it exists and configuration says to
-	if d.conf.Install.DB.Drop {
-		d.traceMsg("Dropping any existing database per Install.DB.Drop=True in dojoConfig.yml")
-		// Query PostgreSQL to see if the configured database name exists already
-		dbCk := sqlStr{
-			os:     t.id,
-			sql:    "\\l",
-			errMsg: "Unable to check for existing DefectDojo PostgreSQL database",
-			creds:  creds,
-			kind:   "inspect",
-		}
-		out, err := runPgSQLCmd(d, dbCk)
+	if d.conf.Install.DB.Drop && exists > 0 {
+		err = backupBeforeDrop(d, db, creds)
 		if err != nil {
-			d.traceMsg("Check for existing DefectDojo PostgreSQL database failed")
-			fmt.Println("Drop database set to true but no database found, continuing")
+			return err
 		}
-
-		// Clean up stdout from inspectCmd output
-		strOut := squishSlice(out)
-		ck := pgParseDBList(d, strOut)
-
-		// Check if there's an existing DB
-		// if ck = 0 then DB doesn't exist
-		// if ck = 1 then the DB exists already and needs to be dropped first
-		if ck == 1 {
-			d.traceMsg("DB EXISTS so droping that sucker")
-			dropDB := sqlStr{
-				os:     t.id,
-				sql:    "DROP DATABASE IF EXISTS " + d.conf.Install.DB.Name + ";",
-				errMsg: "Unable to drop the existing PostgreSQL database",
-				creds:  creds,
-				kind:   "try",
-			}
-			_, err := runPgSQLCmd(d, dropDB)
-			if err != nil {
-				d.traceMsg("Failed to drop existing database per configured option to drop existing")
-				return err
-			}
-			fmt.Printf("Existing database %+v dropped since Database Drop was set to %+v\n",
-				d.conf.Install.DB.Name, d.conf.Install.DB.Drop)
+		d.traceMsg("Dropping existing database per Install.DB.Drop=True in dojoConfig.yml")
+		err = dbExec(d, db, "Unable to drop the existing PostgreSQL database", "DROP DATABASE "+name)
+		if err != nil {
+			d.traceMsg("Failed to drop existing database per configured option to drop existing")
+			return err
 		}
-
+		fmt.Printf("Existing database %+v dropped since Database Drop was set to %+v\n",
+			d.conf.Install.DB.Name, d.conf.Install.DB.Drop)
+		exists = 0
 	}
 
 	// Create the DefectDojo database if it doesn't already exist
-	d.traceMsg("Creating database for DefectDojo on PostgreSQL")
-	createDB := sqlStr{
-		os:     t.id,
-		sql:    "CREATE DATABASE " + d.conf.Install.DB.Name + ";",
-		errMsg: "Unable to create a new PostgreSQL database for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runPgSQLCmd(d, createDB)
-	if err != nil {
-		d.traceMsg("Failed to create new database for DefectDojo to use")
-		// TODO: DEGUGGING
-		//return err
+	if exists == 0 {
+		d.traceMsg("Creating database for DefectDojo on PostgreSQL")
+		err = dbExec(d, db, "Unable to create a new PostgreSQL database for DefectDojo", "CREATE DATABASE "+name)
+		if err != nil {
+			d.traceMsg("Failed to create new database for DefectDojo to use")
+			return err
+		}
 	}
 
 	// Drop user DefectDojo uses to connect to the database
-	d.traceMsg("Dropping existing DefectDojo PostgreSQL DB user, if any")
-	dropUsr := sqlStr{
-		os:     t.id,
-		sql:    "DROP owned by " + d.conf.Install.DB.User + "; DROP USER IF EXISTS " + d.conf.Install.DB.User + ";",
-		errMsg: "Unable to delete existing database user for DefectDojo or one didn't exist",
-		creds:  creds,
-		kind:   "inspect",
-	}
-	out, err := runPgSQLCmd(d, dropUsr)
+	user := pq.QuoteIdentifier(d.conf.Install.DB.User)
+	n, err := dbCount(d, db, "Unable to check for an existing DefectDojo PostgreSQL user",
+		"SELECT COUNT(*) FROM pg_roles WHERE rolname = $1", d.conf.Install.DB.User)
 	if err != nil {
-		// No reason to return the error as this is expected for most cases
-		// and create user will error out for edge cases
-		d.traceMsg("Unable to delete existing database user for DefectDojo or one didn't exist")
-		d.traceMsg(fmt.Sprintf("SQL DROP command output was %+v (in any)", squishSlice(out)))
-		d.traceMsg("Continuing after error deleting user, non-fatal error")
+		return err
+	}
+	if n > 0 {
+		d.traceMsg("Dropping existing DefectDojo PostgreSQL DB user")
+		err = dbExec(d, db, "Unable to delete existing database user for DefectDojo", "DROP OWNED BY "+user)
+		if err == nil {
+			err = dbExec(d, db, "Unable to delete existing database user for DefectDojo", "DROP USER "+user)
+		}
+		if err != nil {
+			// Create user will error out if the user is still there
+			d.traceMsg(fmt.Sprintf("Continuing after error deleting user, non-fatal error: %+v", err))
+		}
 	}
 
 	// Create user for DefectDojo to use to connect to the database
+	// PostgreSQL can't take parameters in CREATE USER so the password is quoted
 	d.traceMsg("Creating PostgreSQL DB user for DefectDojo")
-	createUsr := sqlStr{
-		os: t.id,
-		sql: "CREATE USER " + d.conf.Install.DB.User + " WITH ENCRYPTED PASSWORD '" +
-			d.conf.Install.DB.Pass + "';",
-		errMsg: "Unable to create a PostgreSQL database user for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runPgSQLCmd(d, createUsr)
+	err = dbExec(d, db, "Unable to create a PostgreSQL database user for DefectDojo",
+		"CREATE USER "+user+" WITH ENCRYPTED PASSWORD "+pgPassLiteral(d, d.conf.Install.DB.Pass))
 	if err != nil {
 		d.traceMsg("Failed to create database user for DefectDojo")
 		return err
@@ -645,14 +735,8 @@ func prepPostgreSQL(d *DDConfig, t *targetOS) error {
 	}
 	// Grant the DefectDojo db user the necessary privileges
 	d.traceMsg("Granting privileges to DefectDojo PostgreSQL DB user")
-	grantPrivs := sqlStr{
-		os:     t.id,
-		sql:    "GRANT ALL PRIVILEGES ON DATABASE " + d.conf.Install.DB.Name + " TO " + d.conf.Install.DB.User + ";",
-		errMsg: "Unable to grant needed privileges to database user for DefectDojo",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runPgSQLCmd(d, grantPrivs)
+	err = dbExec(d, db, "Unable to grant needed privileges to database user for DefectDojo",
+		"GRANT ALL PRIVILEGES ON DATABASE "+name+" TO "+user)
 	if err != nil {
 		d.traceMsg("Failed to grant privileges to database user for DefectDojo")
 		return err
@@ -660,14 +744,8 @@ func prepPostgreSQL(d *DDConfig, t *targetOS) error {
 
 	// Set the DefectDojo db user as the owner of dojodb
 	d.traceMsg("Seting DefectDojo db user as the owner of the DB")
-	setPrivs := sqlStr{
-		os:     t.id,
-		sql:    "ALTER DATABASE " + d.conf.Install.DB.Name + " OWNER TO " + d.conf.Install.DB.User + ";",
-		errMsg: "Unable to set database user as owner of DefectDojo DB",
-		creds:  creds,
-		kind:   "try",
-	}
-	_, err = runPgSQLCmd(d, setPrivs)
+	err = dbExec(d, db, "Unable to set database user as owner of DefectDojo DB",
+		"ALTER DATABASE "+name+" OWNER TO "+user)
 	if err != nil {
 		d.traceMsg("Failed to set ownership to database user for DefectDojo DB")
 		return err
@@ -683,48 +761,9 @@ func prepPostgreSQL(d *DDConfig, t *targetOS) error {
 	return nil
 }
 
-func runPgSQLCmd(d *DDConfig, c sqlStr) ([]string, error) {
-	out := make([]string, 1)
-	d.traceMsg(fmt.Sprintf("Postgres query: %s", c.sql))
-	DBCmds := osCmds{
-		id: c.os,
-		cmds: []string{"sudo -i -u postgres PGPASSWORD=\"" + c.creds["pass"] + "\"" +
-			" psql --host=" + d.conf.Install.DB.Host +
-			" --username=" + c.creds["user"] +
-			" --port=" + strconv.Itoa(d.conf.Install.DB.Port) +
-			" --command=\"" + c.sql + "\""},
-		errmsg: []string{c.errMsg},
-		hard:   []bool{false},
-	}
-
-	// Swicht on how to run the command aka runCmds, tryCmds, inspectCmds
-	switch c.kind {
-	case "try":
-		err := tryCmds(d, DBCmds)
-		// Handle errors from running the PostgreSQL command
-		if err != nil {
-			d.traceMsg(fmt.Sprintf("Error running Posgres command - %s", c.sql))
-			return out, err
-		}
-	case "inspect":
-		out, err := inspectCmds(d, DBCmds)
-		// Handle errors from running the PostgreSQL command
-		if err != nil {
-			d.traceMsg(fmt.Sprintf("Error running Posgres command - %s", c.sql))
-			return out, err
-		}
-	default:
-		d.traceMsg("Invalid 'kind' sent to runPgSQLCmd, bug in godojo")
-		fmt.Println("Bug discovered in godojo, see trace message. Quitting.")
-		os.Exit(1)
-	}
-
-	return out, nil
-}
-
 func updatePgHba(d *DDConfig, t *targetOS) bool {
-	// Only RHEL and binary compatible distros (e.g. Rocky Linux) need to have pg_hba.conf modified)
-	if !strings.Contains(t.distro, "rhel") {
+	// Only RHEL and binary compatible distros (e.g. Rocky Linux), SUSE and Amazon Linux need to have pg_hba.conf modified)
+	if !strings.Contains(t.distro, "rhel") && t.distro != "suse" && t.distro != "amzn" {
 		// return early
 		return true
 	}
@@ -735,12 +774,12 @@ func updatePgHba(d *DDConfig, t *targetOS) bool {
 		return true
 	}
 
-	d.traceMsg("RHEL or variant - pg_hba.conf needs to be updated.")
-	f, err := os.OpenFile("/var/lib/pgsql/data/pg_hba.conf", os.O_RDWR, 0600)
+	d.traceMsg(fmt.Sprintf("%s - pg_hba.conf needs to be updated.", t.id))
+	f, err := os.OpenFile(pgHbaPath(d, t), os.O_RDWR, 0600)
 	if err != nil {
 		// Exit with error code if we can't read the default creds file
 		d.errorMsg("Unable to read pg_hba.conf file, cannot continue")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 	defer f.Close()
 
@@ -767,7 +806,7 @@ func updatePgHba(d *DDConfig, t *targetOS) bool {
 	if err = scanner.Err(); err != nil {
 		// Exit with error code if we can't scan the default creds file
 		d.errorMsg("Unable to scan the pg_hba.conf file, exiting")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 
 	// Truncate the file to make sure its empty before writing
@@ -778,103 +817,51 @@ func updatePgHba(d *DDConfig, t *targetOS) bool {
 	if err != nil {
 		// Exit with error code if we can't scan the default creds file
 		d.errorMsg("Unable to write the pg_hba.conf file, exiting")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 	d.traceMsg("Wrote the updated config file")
 
-	// Reload pg_hba.conf using a SQL statement
+	// Reload pg_hba.conf as the postgres OS user since the old config may not allow password logins
 	d.traceMsg("Re-reading the pg_hba.conf file")
-	creds := map[string]string{"user": d.conf.Install.DB.Ruser, "pass": d.conf.Install.DB.Rpass}
-	DBCmds := osCmds{
-		id: t.id,
-		cmds: []string{"sudo -i -u postgres PGPASSWORD=\"" + creds["pass"] + "\"" +
-			" psql " + " --username=" + creds["user"] +
-			" --port=" + strconv.Itoa(d.conf.Install.DB.Port) +
-			" --command=\"SELECT pg_reload_conf();\""},
-		errmsg: []string{"Unable to reload the pg_hba.conf file"},
-		hard:   []bool{false},
-	}
-	err = tryCmds(d, DBCmds)
+	err = psqlAsPostgres(d, "SELECT pg_reload_conf();", "Unable to reload the pg_hba.conf file")
 	if err != nil {
 		d.traceMsg("Unable to reload the pg_hba.conf file")
 		d.errorMsg("Unable to reload the pg_hba.conf file, exiting")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
-	d.traceMsg("Restarted PostgreSQL")
+	d.traceMsg("Reloaded PostgreSQL's config")
 
 	return true
 }
 
-// TODO: REPLACE THIS WITH CLIENT CALLS
-func isPgReady(d *DDConfig, creds map[string]string) (string, error) {
-	d.traceMsg("isPgReady called")
-
-	// Call ps_isready and check exit code
-	pgReady := osCmds{
-		id: "Linux", cmds: []string{"PGPASSWORD=\"" + creds["pass"] + "\" pg_isready" +
-			" --host=" + d.conf.Install.DB.Host +
-			" --username=" + creds["user"] +
-			" --port=" + strconv.Itoa(d.conf.Install.DB.Port) + " "},
-		errmsg: []string{"Unable to run pg_isready to validate PostgreSQL DB status."},
-		hard:   []bool{false},
-	}
-
-	d.traceMsg(fmt.Sprintf("Running command: %+v", pgReady.cmds))
-
-	out, err := inspectCmds(d, pgReady)
-	if err != nil {
-		d.traceMsg(fmt.Sprintf("Error running pg_isready was: %+v", err))
-		// TODO Fix this error bypass
-		return squishSlice(out), nil
-		//return "", err
+// pgDataDir returns the data directory of the local PostgreSQL for the
+// configured version, distro and repo
+func pgDataDir(d *DDConfig, t *targetOS) string {
+	setDBVersion(d, t)
+	v := d.conf.Install.DB.Version
+	switch {
+	case t.distro == "ubuntu" || t.distro == "debian":
+		return "/var/lib/postgresql/" + v + "/main"
+	case t.distro == "alpine":
+		return "/var/lib/postgresql/" + v + "/data"
+	case d.conf.Install.DB.PGDG:
+		return "/var/lib/pgsql/" + v + "/data"
 	}
 
-	return squishSlice(out), nil
+	// RHEL, SUSE and Amazon Linux packages use the same directory for every version
+	return "/var/lib/pgsql/data"
 }
 
-// Parse a list of existng PostgreSQL DBs for a specific DB name
-// if the DB name is found, return 1 else return 0
-func pgParseDBList(d *DDConfig, tbl string) int {
-	d.traceMsg(fmt.Sprintf("Parsing DB list for existing DefectDojo DB named  %+v", d.conf.Install.DB.Name))
-
-	// Create a slice for matches
-	matches := make([]string, 1)
-
-	// Split up the string by newlines
-	lines := strings.Split(tbl, "\n")
-	for _, l := range lines {
-		trim := strings.TrimLeft(l, " ")
-		if len(trim) > 1 {
-			// Look at the first character
-			// Possibly a lossy match but doubtful
-			switch trim[0:1] {
-			case "L":
-				continue
-			case "N":
-				continue
-			case "-":
-				continue
-			case "|":
-				continue
-			case "(":
-				continue
-			default:
-				cells := strings.Split(trim, "|")
-				matches = append(matches, strings.TrimSpace(cells[0]))
-			}
-		}
-	}
+// pgHbaPath returns the pg_hba.conf file of the local PostgreSQL
+func pgHbaPath(d *DDConfig, t *targetOS) string {
+	setDBVersion(d, t)
 
-	// Look for matches and return 1 if a match is found
-	for _, v := range matches {
-		if strings.Contains(v, d.conf.Install.DB.Name) {
-			d.traceMsg("Match found, there is an existing DefectDojo DB")
-			return 1
-		}
+	// Debian and Ubuntu keep config files out of the data directory
+	if t.distro == "ubuntu" || t.distro == "debian" {
+		return "/etc/postgresql/" + d.conf.Install.DB.Version + "/main/pg_hba.conf"
 	}
 
-	d.traceMsg("No match found, no existing DefectDojo DB")
-	return 0
+	return filepath.Join(pgDataDir(d, t), "pg_hba.conf")
 }
 
 func trimRequirementsTxt(d *DDConfig, dbUsed string) error {
@@ -885,10 +872,29 @@ func trimRequirementsTxt(d *DDConfig, dbUsed string) error {
 
 }
 
+// mariaDBSocket are the distros whose MySQL packages are MariaDB, which lets
+// root connect over the unix socket without a password on a fresh install
+var mariaDBSocket = map[string]bool{
+	"debian": true,
+	"rhel":   true,
+	"suse":   true,
+	"amzn":   true,
+	"alpine": true,
+}
+
 func defaultDBCreds(d *DDConfig, os string) map[string]string {
 	// Setup a map to return
 	creds := map[string]string{"user": "foo", "pass": "bar"}
 
+	// MariaDB on these distros allows root to connect over the unix socket without a password
+	distro, _, _ := strings.Cut(strings.ToLower(os), ":")
+	if d.conf.Install.DB.Engine == "MariaDB" || (mariaDBSocket[distro] && d.conf.Install.DB.Engine == "MySQL") {
+		d.traceMsg(fmt.Sprintf("Using root over the unix socket for %s's MariaDB", distro))
+		creds["user"] = "root"
+		creds["pass"] = ""
+		return creds
+	}
+
 	getDefaultDBCreds(d, creds)
 
 	return creds
@@ -901,7 +907,6 @@ func getDefaultDBCreds(d *DDConfig, creds map[string]string) {
 	switch d.conf.Install.DB.Engine {
 	case "MySQL":
 		ubuntuDefaultMySQL(d, creds)
-		d.warnMsg("MySQL default credentials are not implemented for RHEL Linux")
 	case "PostgreSQL":
 		// Set creds as the Ruser & Rpass for Postgres
 		creds["user"] = d.conf.Install.DB.Ruser
@@ -920,7 +925,7 @@ func ubuntuDefaultMySQL(d *DDConfig, c map[string]string) {
 	if err != nil {
 		// Exit with error code if we can't read the default creds file
 		d.errorMsg("Unable to read file with defautl credentials, cannot continue")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 
 	// Create a new buffered reader
@@ -942,7 +947,7 @@ func ubuntuDefaultMySQL(d *DDConfig, c map[string]string) {
 	if err = scanner.Err(); err != nil {
 		// Exit with error code if we can't scan the default creds file
 		d.errorMsg("Unable to scan file with defautl credentials, cannot continue")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 
 }
@@ -954,19 +959,12 @@ func setDefaultPgSQL(d *DDConfig, creds map[string]string) {
 	creds["user"] = "postgres"
 
 	// Use the default local OS user to set the postgres DB user
-	pgAlter := osCmds{
-		id:     "linux",
-		cmds:   []string{"sudo -i -u postgres psql -c \"ALTER USER postgres with encrypted password '" + creds["pass"] + "';\""},
-		errmsg: []string{"Unable to set initial password for PostgreSQL database user postgres"},
-		hard:   []bool{false},
-	}
-
-	// Try command
-	err := tryCmds(d, pgAlter)
+	err := psqlAsPostgres(d, "ALTER USER postgres WITH ENCRYPTED PASSWORD "+pgPassLiteral(d, creds["pass"])+";",
+		"Unable to set initial password for PostgreSQL database user postgres")
 	if err != nil {
-		d.traceMsg(fmt.Sprintf("Error updating PostgreSQL DB user with %+v", squishSlice(pgAlter.cmds)))
+		d.traceMsg(fmt.Sprintf("Error updating PostgreSQL DB user with %+v", err))
 		d.errorMsg("Unable to update default PostgreSQL DB user, quitting")
-		os.Exit(1)
+		d.exitWith(exitDBPrep)
 	}
 
 	d.traceMsg("No error return from setDefaultPgSQL")
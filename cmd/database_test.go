@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDatabaseURL(t *testing.T) {
+	d := &DDConfig{}
+	d.conf.Install.DB = dBTarget{Engine: "PostgreSQL", Name: "dojodb", User: "dojodbusr", Host: "db.example.com", Port: 5432}
+	for _, pass := range []string{"plain", `it's"@/:$#%?`, "with space"} {
+		d.conf.Install.DB.Pass = pass
+		u, err := url.Parse(databaseURL(d))
+		if err != nil {
+			t.Fatalf("Unable to parse DD_DATABASE_URL for password %q: %v", pass, err)
+		}
+		got, _ := u.User.Password()
+		if got != pass || u.Host != "db.example.com:5432" || u.Path != "/dojodb" || u.Scheme != "postgres" {
+			t.Errorf("DD_DATABASE_URL %s doesn't round trip password %q", u, pass)
+		}
+	}
+}
+
+func TestDBConfigProblems(t *testing.T) {
+	db := dBTarget{Engine: "MySQL", Name: "dojodb", User: "dojodbusr", Pass: `'@/"$`, Rpass: "root"}
+	if p := dbConfigProblems(db, "admin"); len(p) != 0 {
+		t.Errorf("Expecting no problems, got %v", p)
+	}
+
+	db.Name, db.User, db.Pass = "dojo`db", "1user", "line\nbreak"
+	if p := dbConfigProblems(db, "tab\there"); len(p) != 4 {
+		t.Errorf("Expecting 4 problems, got %v", p)
+	}
+
+	if got := mysqlIdent("dojo`db"); got != "`dojo``db`" {
+		t.Errorf("Expecting the backtick to be doubled, got %s", got)
+	}
+}
+
+func TestPgPassLiteralRedacted(t *testing.T) {
+	for _, pass := range []string{`it's`, `back\slash`, `both'\`} {
+		d := &DDConfig{}
+		d.addRedact(pass)
+		stmt := "CREATE USER dojo WITH ENCRYPTED PASSWORD " + pgPassLiteral(d, pass)
+		if got := d.redactatron(stmt, true); got != "CREATE USER dojo WITH ENCRYPTED PASSWORD [~REDACTED~]" {
+			t.Errorf("Password %q not redacted from %s", pass, got)
+		}
+	}
+}
+
+func TestMissingMySQLPrivs(t *testing.T) {
+	cases := []struct {
+		name    string
+		grants  []string
+		drop    bool
+		missing string
+		roles   bool
+	}{
+		{"global all", []string{"GRANT ALL PRIVILEGES ON *.* TO `admin`@`%` WITH GRANT OPTION"}, true, "", false},
+		{"global all without grant option", []string{"GRANT ALL PRIVILEGES ON *.* TO `admin`@`%`"}, false, "GRANT OPTION", false},
+		{"per-db all", []string{
+			"GRANT USAGE ON *.* TO `admin`@`%`",
+			"GRANT ALL PRIVILEGES ON `dojo_db`.* TO `admin`@`%` WITH GRANT OPTION",
+		}, true, "CREATE USER", false},
+		{"escaped per-db name", []string{
+			"GRANT CREATE USER ON *.* TO `admin`@`%` WITH GRANT OPTION",
+			"GRANT CREATE, DROP ON `dojo\\_db`.* TO `admin`@`%`",
+		}, true, "", false},
+		{"other db", []string{
+			"GRANT CREATE USER ON *.* TO `admin`@`%`",
+			"GRANT ALL PRIVILEGES ON `other`.* TO `admin`@`%` WITH GRANT OPTION",
+		}, true, "CREATE,GRANT OPTION,DROP", false},
+		{"mysql 8 with dynamic privileges", []string{
+			"GRANT SELECT, INSERT, CREATE, DROP, RELOAD, CREATE USER ON *.* TO `admin`@`%` WITH GRANT OPTION",
+			"GRANT APPLICATION_PASSWORD_ADMIN,AUDIT_ADMIN,BACKUP_ADMIN,SYSTEM_USER ON *.* TO `admin`@`%` WITH GRANT OPTION",
+		}, true, "", false},
+		{"drop only needed with Drop", []string{"GRANT CREATE, CREATE USER ON *.* TO `admin`@`%` WITH GRANT OPTION"}, false, "", false},
+		{"role only", []string{
+			"GRANT USAGE ON *.* TO `admin`@`%`",
+			"GRANT `dba`@`%` TO `admin`@`%`",
+		}, false, "CREATE,CREATE USER,GRANT OPTION", true},
+	}
+	for _, c := range cases {
+		missing, roles := missingMySQLPrivs(c.grants, "dojo_db", c.drop)
+		if got := strings.Join(missing, ","); got != c.missing || roles != c.roles {
+			t.Errorf("%s: expecting %q missing and roles %v, got %q and %v", c.name, c.missing, c.roles, got, roles)
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	cases := []struct {
+		ver, min string
+		want     bool
+	}{
+		{"8.0.36-0ubuntu0.22.04.1", "8.0", true},
+		{"8.0.36-0ubuntu0.22.04.1", "8.1", false},
+		{"10.5.22-MariaDB", "10.4", true},
+		{"10.5.22-MariaDB", "10.5.23", false},
+		{"10.5.22-MariaDB-log", "10.5.22", true},
+		{"5.7.44", "8.0", false},
+		{"bogus", "8.0", false},
+	}
+	for _, c := range cases {
+		if got := versionAtLeast(c.ver, c.min); got != c.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v", c.ver, c.min, got)
+		}
+	}
+}
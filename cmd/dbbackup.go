@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default for Install.DB.BackupDir, outside Install.Root so a fresh install
+// there doesn't remove it
+var defaultBackupDir = "/var/backups/godojo"
+
+// backupBeforeDrop backs up the existing DefectDojo database before Drop
+// removes it.  Databases with tables are only dropped with --confirm-drop.
+func backupBeforeDrop(d *DDConfig, db *sql.DB, creds map[string]string) error {
+	if d.freshDB {
+		d.traceMsg("Database was created by this run, dropping it without a backup")
+		return nil
+	}
+	tables, err := dbTableCount(d, db, creds)
+	if err != nil {
+		return err
+	}
+	if tables > 0 && !d.confirmDrop {
+		return fmt.Errorf("Install.DB.Drop is true but database %s has %d tables, nothing was dropped. "+
+			"Check Install.DB.Name then run godojo again with --confirm-drop to back it up to %s and drop it",
+			d.conf.Install.DB.Name, tables, backupDir(d))
+	}
+
+	d.statusMsg(fmt.Sprintf("Backing up database %s before dropping it", d.conf.Install.DB.Name))
+	p, err := backupDB(d, creds)
+	if err != nil {
+		return fmt.Errorf("Unable to back up database %s so nothing was dropped: %w", d.conf.Install.DB.Name, err)
+	}
+	d.dbBackup = p
+	d.statusMsg(fmt.Sprintf("Backed up database %s to %s", d.conf.Install.DB.Name, p))
+
+	return nil
+}
+
+// dbTableCount returns the number of tables in the DefectDojo database
+func dbTableCount(d *DDConfig, db *sql.DB, creds map[string]string) (int, error) {
+	errMsg := "Unable to count the tables in database " + d.conf.Install.DB.Name
+	switch d.conf.Install.DB.Engine {
+	case "SQLite":
+		fi, err := os.Stat(sqlitePath(d))
+		if err != nil || fi.Size() == 0 {
+			return 0, nil
+		}
+		// Counting tables would need a SQLite driver, any content is treated as a table
+		return 1, nil
+	case "PostgreSQL":
+		// The connection in db is to the postgres database
+		pdb, err := pgOpenDB(d, creds, d.conf.Install.DB.Name)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", errMsg, err)
+		}
+		defer pdb.Close()
+		return dbCount(d, pdb, errMsg,
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema NOT IN ('pg_catalog', 'information_schema')")
+	}
+
+	return dbCount(d, db, errMsg, "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ?", d.conf.Install.DB.Name)
+}
+
+// backupDir returns the directory for database backups
+func backupDir(d *DDConfig) string {
+	if d.conf.Install.DB.BackupDir == "" {
+		return defaultBackupDir
+	}
+
+	return d.conf.Install.DB.BackupDir
+}
+
+// backupDB writes a gzip compressed dump of the DefectDojo database to the
+// backup directory and returns its path
+func backupDB(d *DDConfig, creds map[string]string) (string, error) {
+	err := os.MkdirAll(backupDir(d), 0700)
+	if err != nil {
+		return "", err
+	}
+	ext := ".sql.gz"
+	if d.conf.Install.DB.Engine == "SQLite" {
+		ext = ".sqlite3.gz"
+	}
+	p := filepath.Join(backupDir(d), d.conf.Install.DB.Name+"-"+time.Now().Format("20060102-150405")+ext)
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+
+	err = dumpDB(d, creds, zw)
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		// Don't leave a partial backup that looks usable
+		f.Close()
+		os.Remove(p)
+		return "", err
+	}
+
+	return p, nil
+}
+
+// dumpDB writes a dump of the DefectDojo database to w with mysqldump or
+// pg_dump.  Passwords are passed in the environment, not the command line.
+func dumpDB(d *DDConfig, creds map[string]string, w io.Writer) error {
+	var dump *exec.Cmd
+	switch d.conf.Install.DB.Engine {
+	case "SQLite":
+		f, err := os.Open(sqlitePath(d))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	case "MySQL", "MariaDB":
+		dump = dbClientCmd(d, creds, "mysqldump", "--single-transaction", "--no-tablespaces")
+	case "PostgreSQL":
+		dump = dbClientCmd(d, creds, "pg_dump", "--no-owner", "--no-privileges")
+	default:
+		return fmt.Errorf("Unable to back up the %s engine", d.conf.Install.DB.Engine)
+	}
+
+	d.traceMsg(fmt.Sprintf("Dumping database with %s", dump.String()))
+	d.cmdLogger.Printf("[godojo] # %s\n", dump.String())
+	dump.Stdout = w
+	dump.Stderr = d.cmdLogger.Writer()
+	err := d.runChild(dump)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", dump.Path, err)
+	}
+
+	return nil
+}
+
+// loadDB loads a dump written by dumpDB into the DefectDojo database with
+// the mysql or psql client
+func loadDB(d *DDConfig, creds map[string]string, r io.Reader) error {
+	var load *exec.Cmd
+	switch d.conf.Install.DB.Engine {
+	case "SQLite":
+		f, err := os.OpenFile(sqlitePath(d), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(f, r)
+		if err != nil {
+			return err
+		}
+		return chownDojo(d, sqlitePath(d))
+	case "MySQL", "MariaDB":
+		load = dbClientCmd(d, creds, "mysql", "--batch")
+	case "PostgreSQL":
+		load = dbClientCmd(d, creds, "psql", "--no-psqlrc", "--quiet", "--single-transaction", "-v", "ON_ERROR_STOP=1")
+	default:
+		return fmt.Errorf("Unable to restore the %s engine", d.conf.Install.DB.Engine)
+	}
+
+	d.traceMsg(fmt.Sprintf("Loading database with %s", load.String()))
+	d.cmdLogger.Printf("[godojo] # %s\n", load.String())
+	load.Stdin = r
+	load.Stdout = d.cmdLogger.Writer()
+	load.Stderr = d.cmdLogger.Writer()
+	err := d.runChild(load)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", load.Path, err)
+	}
+
+	return nil
+}
+
+// dbClientCmd returns a MySQL or PostgreSQL client command connecting to the
+// DefectDojo database as the user in creds.  The password is set in the
+// environment so it isn't in the process list.
+func dbClientCmd(d *DDConfig, creds map[string]string, client string, args ...string) *exec.Cmd {
+	port := strconv.Itoa(d.conf.Install.DB.Port)
+	if strings.HasPrefix(client, "mysql") {
+		args = append(args, "--user="+creds["user"])
+		if s := mysqlSocket(d); s != "" {
+			args = append(args, "--socket="+s)
+		} else {
+			args = append(args, "--host="+d.conf.Install.DB.Host, "--port="+port)
+		}
+		cmd := exec.Command(client, append(args, d.conf.Install.DB.Name)...)
+		cmd.Env = append(os.Environ(), "MYSQL_PWD="+creds["pass"])
+		return cmd
+	}
+
+	args = append(args, "--host="+d.conf.Install.DB.Host, "--port="+port, "--username="+creds["user"], "--dbname="+d.conf.Install.DB.Name)
+	cmd := exec.Command(client, args...)
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+creds["pass"])
+	return cmd
+}
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// How long to wait when connecting to the database server
+var dbConnTimeout = 10 * time.Second
+
+// Unix sockets used by local MySQL and MariaDB servers, the mysql client
+// connects to these when the host is localhost
+var mysqlSockets = []string{
+	"/run/mysqld/mysqld.sock",
+	"/var/run/mysqld/mysqld.sock",
+	"/var/lib/mysql/mysql.sock",
+	"/run/mysql/mysql.sock",
+}
+
+// dbOpen connects to the configured database server as the user in creds.
+// PostgreSQL connections use the postgres database since DefectDojo's
+// database may not exist yet.
+func dbOpen(d *DDConfig, creds map[string]string) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+	switch d.conf.Install.DB.Engine {
+	case "MySQL", "MariaDB":
+		db, err = mysqlOpen(d, creds)
+	case "PostgreSQL":
+		db, err = pgOpen(d, creds)
+	default:
+		return nil, fmt.Errorf("No database driver for the %s engine", d.conf.Install.DB.Engine)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errDBUnreachable, err)
+	}
+
+	return db, nil
+}
+
+// mysqlOpen connects to MySQL or MariaDB, over the unix socket if the host
+// is localhost like the mysql client does
+func mysqlOpen(d *DDConfig, creds map[string]string) (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = creds["user"]
+	cfg.Passwd = creds["pass"]
+	cfg.Timeout = dbConnTimeout
+	// MySQL can't prepare most DDL so have the driver escape the arguments
+	cfg.InterpolateParams = true
+
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(d.conf.Install.DB.Host, strconv.Itoa(d.conf.Install.DB.Port))
+	if s := mysqlSocket(d); s != "" {
+		cfg.Net, cfg.Addr = "unix", s
+	}
+	d.traceMsg(fmt.Sprintf("Connecting to %s over %s %s as %s", d.conf.Install.DB.Engine, cfg.Net, cfg.Addr, cfg.User))
+
+	conn, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(conn)
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// mysqlSocket returns the unix socket of a local MySQL or MariaDB server if
+// the host is localhost, or an empty string to connect over TCP
+func mysqlSocket(d *DDConfig) string {
+	if d.conf.Install.DB.Host != "localhost" {
+		return ""
+	}
+	for _, s := range mysqlSockets {
+		if _, err := os.Stat(s); err == nil {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// pgOpen connects to PostgreSQL's postgres database
+func pgOpen(d *DDConfig, creds map[string]string) (*sql.DB, error) {
+	return pgOpenDB(d, creds, "postgres")
+}
+
+// pgOpenDB connects to a PostgreSQL database.  TLS is required for remote
+// servers unless the server doesn't support it, the same as psql's default
+// of prefer.
+func pgOpenDB(d *DDConfig, creds map[string]string, name string) (*sql.DB, error) {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(creds["user"], creds["pass"]),
+		Host:   net.JoinHostPort(d.conf.Install.DB.Host, strconv.Itoa(d.conf.Install.DB.Port)),
+		Path:   "/" + name,
+	}
+	modes := []string{"require", "disable"}
+	if d.conf.Install.DB.Local {
+		modes = []string{"disable"}
+	}
+
+	var err error
+	for _, m := range modes {
+		q := url.Values{}
+		q.Set("sslmode", m)
+		q.Set("connect_timeout", strconv.Itoa(int(dbConnTimeout.Seconds())))
+		u.RawQuery = q.Encode()
+		d.traceMsg(fmt.Sprintf("Connecting to PostgreSQL on %s as %s with sslmode %s", u.Host, creds["user"], m))
+
+		var conn *pq.Connector
+		conn, err = pq.NewConnector(u.String())
+		if err != nil {
+			return nil, err
+		}
+		db := sql.OpenDB(conn)
+		err = db.Ping()
+		if err == nil {
+			return db, nil
+		}
+		db.Close()
+		if !errors.Is(err, pq.ErrSSLNotSupported) {
+			break
+		}
+		d.traceMsg("PostgreSQL server doesn't support TLS, connecting without it")
+	}
+
+	return nil, err
+}
+
+// dbExec runs a statement that doesn't return rows, adding errMsg to any error
+func dbExec(d *DDConfig, db *sql.DB, errMsg string, query string, args ...interface{}) error {
+	d.traceMsg(fmt.Sprintf("SQL statement: %s", query))
+	_, err := db.Exec(query, args...)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("SQL statement failed with %+v", err))
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+
+	return nil
+}
+
+// dbCount runs a query returning a single count e.g. of matching databases
+func dbCount(d *DDConfig, db *sql.DB, errMsg string, query string, args ...interface{}) (int, error) {
+	d.traceMsg(fmt.Sprintf("SQL query: %s", query))
+	n := 0
+	err := db.QueryRow(query, args...).Scan(&n)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("SQL query failed with %+v", err))
+		return 0, fmt.Errorf("%s: %w", errMsg, err)
+	}
+
+	return n, nil
+}
+
+// mysqlIdent quotes a MySQL identifier like a database name
+func mysqlIdent(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// pgPassLiteral quotes a password for PostgreSQL SQL and redacts the quoted
+// form since quoting changes passwords with ' or \ from the raw value
+func pgPassLiteral(d *DDConfig, pass string) string {
+	lit := pq.QuoteLiteral(pass)
+	d.addRedact(lit)
+
+	return lit
+}
+
+// psqlAsPostgres runs SQL with psql as the postgres OS user for changes
+// needing local peer auth.  The SQL is sent on stdin to keep any passwords
+// out of the process list.
+func psqlAsPostgres(d *DDConfig, query string, errMsg string) error {
+	cmd := "sudo -u postgres psql --no-psqlrc --quiet -v ON_ERROR_STOP=1 --port=" + strconv.Itoa(d.conf.Install.DB.Port)
+	d.traceMsg(fmt.Sprintf("Running %s with SQL %s on stdin", cmd, query))
+	d.cmdLogger.Printf("[godojo] # %s\n", cmd)
+
+	runCmd := exec.Command("bash", "-c", cmd)
+	runCmd.Stdin = strings.NewReader(query + "\n")
+	runCmd.Stdout = d.cmdLogger.Writer()
+	runCmd.Stderr = d.cmdLogger.Writer()
+	err := d.runChild(runCmd)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("%s - %s errored with %+v", timeStamp(), cmd, err))
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+
+	return nil
+}
+
+// Delays between attempts to reach a local database server in waitForDB,
+// only the total wait is configurable with Install.DB.Wait
+const (
+	dbWaitFirst = 500 * time.Millisecond
+	dbWaitMax   = 8 * time.Second
+)
+
+// waitForDB waits for a local database server to answer connections, backing
+// off between attempts, for up to Install.DB.Wait seconds
+func waitForDB(d *DDConfig, t *targetOS) error {
+	wait := time.Duration(d.conf.Install.DB.Wait) * time.Second
+	if wait <= 0 {
+		wait = 120 * time.Second
+	}
+	engine := d.conf.Install.DB.Engine
+	d.statusMsg(fmt.Sprintf("Waiting up to %s for %s to accept connections", wait, engine))
+
+	creds := map[string]string{"user": d.conf.Install.DB.Ruser, "pass": d.conf.Install.DB.Rpass}
+	deadline := time.Now().Add(wait)
+	delay := dbWaitFirst
+	for {
+		err := dbAnswers(d, creds)
+		if err == nil {
+			d.traceMsg(fmt.Sprintf("%s is accepting connections", engine))
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("%s didn't accept connections within %s, the last error was %v%s",
+				engine, wait, err, dbLogTail(d, t, 20))
+		}
+		d.traceMsg(fmt.Sprintf("%s isn't ready, trying again in %s. Error was %v", engine, delay, err))
+		time.Sleep(delay)
+		delay *= 2
+		if delay > dbWaitMax {
+			delay = dbWaitMax
+		}
+	}
+}
+
+// dbAnswers returns nil if the database server responds, including by
+// rejecting the login since a fresh install's credentials aren't set yet
+func dbAnswers(d *DDConfig, creds map[string]string) error {
+	var db *sql.DB
+	var err error
+	switch d.conf.Install.DB.Engine {
+	case "MySQL", "MariaDB":
+		db, err = mysqlOpen(d, creds)
+	case "PostgreSQL":
+		db, err = pgOpen(d, creds)
+	default:
+		return nil
+	}
+	if err == nil {
+		db.Close()
+		return nil
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return nil
+	}
+	// 57P03 is cannot_connect_now, returned while PostgreSQL is starting up
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) && pgErr.Code != "57P03" {
+		return nil
+	}
+
+	return err
+}
+
+// Logs written by local database servers by engine
+var dbLogs = map[string][]string{
+	"MySQL": {
+		"/var/log/mysql/error.log",
+		"/var/log/mysql/mysqld.log",
+		"/var/log/mariadb/mariadb.log",
+		"/var/lib/mysql/*.err",
+	},
+	"PostgreSQL": {
+		"/var/log/postgresql/*.log",
+	},
+}
+
+// dbLogTail returns the last n lines of the newest log of the local database
+// server to add to an error, or an empty string if there isn't one
+func dbLogTail(d *DDConfig, t *targetOS, n int) string {
+	engine := d.conf.Install.DB.Engine
+	if engine == "MariaDB" {
+		engine = "MySQL"
+	}
+	globs := dbLogs[engine]
+	if engine == "PostgreSQL" {
+		globs = append(globs, filepath.Join(pgDataDir(d, t), "log", "*.log"))
+	}
+
+	newest := ""
+	var newestMod time.Time
+	for _, g := range globs {
+		files, _ := filepath.Glob(g)
+		for _, f := range files {
+			fi, err := os.Stat(f)
+			if err == nil && fi.ModTime().After(newestMod) {
+				newest, newestMod = f, fi.ModTime()
+			}
+		}
+	}
+	if newest == "" {
+		d.traceMsg("Unable to find a log for the database server")
+		return ""
+	}
+
+	// Only read the end of the file since server logs can be large
+	f, err := os.Open(newest)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err == nil && fi.Size() > 64*1024 {
+		_, _ = f.Seek(-64*1024, io.SeekEnd)
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return fmt.Sprintf("\nLast lines of %s:\n%s", newest, strings.Join(lines, "\n"))
+}
@@ -5,9 +5,11 @@ import (
 	"io"
 	"log"
 	"os"
+	"os/exec"
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -36,7 +38,31 @@ type DDConfig struct {
 	traceOn     bool             // Runtime flag to turn on trace logging
 	redact      bool             // Runtime flag to redact sensitive info (defaults to on)
 	spin        *spinner.Spinner // Progress spinner
+	follow      bool             // Runtime flag to stream command output live (--follow or --verbose)
+	shell       string           // Shell from the install target's command pack, bash until the OS is known
+	isTTY       bool             // True if stdout is a terminal vs a pipe or file like CI logs
+	events      *eventOut        // Writer for JSON events when --output json is used, nil otherwise
+	phase       string           // Name of the install phase currently running
+	phasesDone  []string         // Install phases that have completed
+	started     time.Time        // When the install started
+	lastCmd     string           // Last OS command run, used when reporting failures
+	locks       []string         // Lock files held by this godojo run
+	lockMu      sync.Mutex       // Guards locks which the signal handler also releases
+	child       *exec.Cmd        // OS command currently running, stopped by the signal handler
+	childDone   chan struct{}    // Closed when child exits
+	childMu     sync.Mutex       // Guards child, childDone and stopping
+	stopping    bool             // True once a signal is received so no new commands start
+	runPhase    map[string]bool  // Install phases to run based on --only and --skip
+	pinsUsed    map[string]bool  // Packages in Install.Pins that a pkgmgr install command has matched
 	defInstall  bool             // Holds command-line bool asking for a default install
+	confirmDrop bool             // Runtime flag to allow Drop to remove a database with tables (--confirm-drop)
+	dbBackup    string           // Path of the backup taken before dropping the database, if any
+	freshDB     bool             // True if this run created the database so dropping it needs no backup
+	subCmd      string           // Subcommand to run instead of an install i.e. backup or restore
+	subArgs     []string         // Arguments to the subcommand
+	archive     string           // Path of the archive written by godojo backup
+	restoreDir  string           // Where godojo restore unpacked the archive
+	restoreFrom backupManifest   // Manifest of the archive being restored
 	emdir       string
 	otdir       string
 	bdir        string
@@ -68,6 +94,9 @@ func (d *DDConfig) setGodojoDefaults() {
 	d.traceOn = true
 	d.redact = true
 	d.defInstall = false
+	d.follow = false
+	d.isTTY = isTerminal(os.Stdout)
+	d.started = time.Now()
 	d.emdir = "embd/"
 	d.otdir = "/tmp/.dojo-temp/"
 	d.bdir = "/opt/"
@@ -106,7 +135,7 @@ func (gd *DDConfig) prepLogging() io.Writer {
 			fmt.Println("##############################################################################")
 			fmt.Println("")
 			fmt.Println("Exiting install")
-			os.Exit(1)
+			os.Exit(exitGeneral)
 		}
 	}
 
@@ -119,7 +148,7 @@ func (gd *DDConfig) prepLogging() io.Writer {
 		fmt.Println("##############################################################################")
 		fmt.Println("")
 		fmt.Println("Log files are required for the install, exiting install")
-		os.Exit(1)
+		os.Exit(exitGeneral)
 	}
 
 	// Return the logfile
@@ -137,6 +166,7 @@ func (gd *DDConfig) sectionMsg(s string) {
 		fmt.Println("==============================================================================")
 		fmt.Println("")
 	}
+	gd.emit(event{Type: evStatus, Message: s})
 	gd.Info.Println("SECTION: " + s)
 }
 
@@ -146,6 +176,7 @@ func (gd *DDConfig) statusMsg(s string) {
 	if !gd.quiet {
 		fmt.Printf("%s\n", gd.redactatron(s, gd.redact))
 	}
+	gd.emit(event{Type: evStatus, Message: s})
 	gd.Info.Println(gd.redactatron(s, gd.redact))
 }
 
@@ -159,6 +190,7 @@ func (gd *DDConfig) warnMsg(s string) {
 		fmt.Println("##############################################################################")
 		fmt.Println("")
 	}
+	gd.emit(event{Type: evWarning, Message: s})
 	gd.Warning.Println(gd.redactatron(s, gd.redact))
 }
 
@@ -172,6 +204,7 @@ func (gd *DDConfig) errorMsg(s string) {
 		fmt.Println("##############################################################################")
 		fmt.Println("")
 	}
+	gd.emit(event{Type: evError, Message: s})
 	gd.Error.Println(gd.redactatron(s, gd.redact))
 }
 
@@ -214,6 +247,7 @@ func (gd *DDConfig) getReplacements() map[string]string {
 	iv["{conf.Install.Admin.User}"] = gd.conf.Install.Admin.User   // Admin user used by DefectDojo web UI
 	iv["{conf.Install.Admin.Email}"] = gd.conf.Install.Admin.Email // Admin user's email address used by DefectDojo web UI
 	iv["{conf.Install.Admin.Pass}"] = gd.conf.Install.Admin.Pass   // Admin user's password for DefectDojo web UI
+	iv["{conf.Install.DB.Version}"] = gd.conf.Install.DB.Version   // PostgreSQL major version e.g. 16
 
 	return iv
 }
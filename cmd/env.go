@@ -75,7 +75,7 @@ func genAndWriteEnv(d *DDConfig, dbURL string) {
 		_, err := rand.Read(s1)
 		if err != nil {
 			d.errorMsg("Error generating random data for encryption keys")
-			os.Exit(1)
+			d.exitWith(exitGeneral)
 		}
 		secretKey = base64.StdEncoding.EncodeToString(s1)
 	}
@@ -86,7 +86,7 @@ func genAndWriteEnv(d *DDConfig, dbURL string) {
 		_, err := rand.Read(s2)
 		if err != nil {
 			d.errorMsg("Error generating random data for encryption keys")
-			os.Exit(1)
+			d.exitWith(exitGeneral)
 		}
 		credentialKey = base64.StdEncoding.EncodeToString(s2)
 	}
@@ -126,7 +126,7 @@ func genAndWriteEnv(d *DDConfig, dbURL string) {
 	f, err := os.Create(d.conf.Install.Root + "/django-DefectDojo/dojo/settings/.env.prod")
 	if err != nil {
 		d.errorMsg("Unable to create .env.prod file for settings.py configuration")
-		os.Exit(1)
+		d.exitWith(exitDjango)
 	}
 	defer f.Close()
 
@@ -134,6 +134,6 @@ func genAndWriteEnv(d *DDConfig, dbURL string) {
 	err = t.Execute(f, env)
 	if err != nil {
 		d.errorMsg("Failed to create .env.prod from template")
-		os.Exit(1)
+		d.exitWith(exitDjango)
 	}
 }
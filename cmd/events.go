@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handles the machine-readable output enabled with --output json where
+// each line written to stdout is a single JSON event
+
+// Version of the event schema, bump this if existing fields change meaning
+// or are removed.  Adding new optional fields does not require a bump.
+const eventSchema = 1
+
+// Supported event types
+const (
+	evPhaseStart  = "phase_start"
+	evPhaseFinish = "phase_finish"
+	evCmdStart    = "command_start"
+	evCmdFinish   = "command_finish"
+	evStatus      = "status"
+	evWarning     = "warning"
+	evError       = "error"
+	evSummary     = "summary"
+)
+
+// event is a single line of JSON output
+type event struct {
+	Schema   int     `json:"schema"`
+	Time     string  `json:"time"`
+	Type     string  `json:"type"`
+	Phase    string  `json:"phase,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Command  string  `json:"command,omitempty"`
+	Step     int     `json:"step,omitempty"`
+	Steps    int     `json:"steps,omitempty"`
+	Status   string  `json:"status,omitempty"`
+	Elapsed  float64 `json:"elapsed_seconds,omitempty"`
+	Version  string  `json:"version,omitempty"`
+	ExitCode int     `json:"exit_code,omitempty"`
+	Backup   string  `json:"backup,omitempty"`
+	Archive  string  `json:"archive,omitempty"`
+}
+
+// eventOut writes JSON events one per line
+type eventOut struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// setOutput takes a pointer to a DDConfig struct and the value of --output
+// and configures how godojo reports progress
+func setOutput(d *DDConfig, o string) {
+	switch o {
+	case "", "text":
+		return
+	case "json":
+		// Events own stdout, anything else printed goes to stderr for humans
+		d.events = &eventOut{w: os.Stdout}
+		os.Stdout = os.Stderr
+		d.quiet = true
+	default:
+		fmt.Printf("Unknown value for --output: %s\nValid values are text and json\n", o)
+		d.exitWith(exitConfig)
+	}
+}
+
+// emit writes an event if JSON output is turned on
+func (d *DDConfig) emit(e event) {
+	if d.events == nil {
+		return
+	}
+	e.Schema = eventSchema
+	e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	if e.Phase == "" {
+		e.Phase = d.phase
+	}
+	e.Message = d.redactatron(strings.TrimSpace(e.Message), d.redact)
+	e.Command = d.redactatron(e.Command, d.redact)
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		d.traceMsg(fmt.Sprintf("Unable to marshal JSON event, error was: %+v", err))
+		return
+	}
+	d.events.mu.Lock()
+	defer d.events.mu.Unlock()
+	_, _ = fmt.Fprintf(d.events.w, "%s\n", line)
+}
+
+// inPhase takes the name of an install phase and the function that does the
+// work for that phase and records when the phase starts and finishes
+func (d *DDConfig) inPhase(name string, f func()) {
+	d.phase = name
+	start := time.Now()
+	d.traceMsg(fmt.Sprintf("Starting phase %s", name))
+	d.emit(event{Type: evPhaseStart})
+
+	if pkgMgrPhases[name] {
+		d.withPkgLock(f)
+	} else {
+		f()
+	}
+
+	d.traceMsg(fmt.Sprintf("Finished phase %s in %s", name, roundDur(time.Since(start))))
+	d.emit(event{Type: evPhaseFinish, Status: "ok", Elapsed: time.Since(start).Seconds()})
+	d.phasesDone = append(d.phasesDone, name)
+	d.phase = ""
+}
+
+// summary emits the final event for the install with the overall status
+func (d *DDConfig) summary(status string, msg string) {
+	d.emit(event{
+		Type:    evSummary,
+		Status:  status,
+		Message: msg,
+		Elapsed: time.Since(d.started).Seconds(),
+		Version: d.ver,
+		Backup:  d.dbBackup,
+		Archive: d.archive,
+	})
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Exit codes used by godojo for each class of failure.  These are documented
+// in the README and used by automation to decide if a failed install should
+// be retried so existing values must not be changed or reused.
+const (
+	exitGeneral     = 1  // Failure that doesn't fit another class e.g. unable to write logs
+	exitConfig      = 2  // Invalid config file, environmental variables or command-line arguments
+	exitUnsupported = 3  // Unsupported OS, distro or release or a missing prerequisite like Python 3.11
+	exitNetwork     = 4  // Downloading the DefectDojo release or source failed
+	exitDBConnect   = 5  // Database is unreachable or rejected the configured credentials
+	exitDBPrep      = 6  // Creating or configuring the database for DefectDojo failed
+	exitOSCmd       = 7  // An OS command failed e.g. installing OS packages
+	exitDjango      = 8  // Setting up Django for DefectDojo failed e.g. pip install or migrations
+	exitPrivs       = 9  // godojo wasn't run with sufficient privileges
+	exitLocked      = 10 // Another godojo run holds the lock for Install.Root or the package manager
+	exitBackup      = 11 // godojo backup or restore failed e.g. the archive doesn't match its checksums
+)
+
+// errDBUnreachable is wrapped by errors returned when godojo is unable to
+// connect to the database so they can be told apart from other DB errors
+var errDBUnreachable = errors.New("unable to connect to the database")
+
+// cmdExitCode returns the exit code for a failed OS command based on the
+// install phase it was run in
+func (d *DDConfig) cmdExitCode() int {
+	switch d.phase {
+	case "prepdb":
+		return exitDBPrep
+	case "prepdjango", "createsettings", "setupdojo", "migrate":
+		return exitDjango
+	}
+
+	return exitOSCmd
+}
+
+// exitWith takes an exit code, writes a final summary naming the install
+// phase and the last command run then exits godojo with that exit code
+func (d *DDConfig) exitWith(code int) {
+	phase := d.phase
+	if phase == "" {
+		phase = "prepinstaller"
+	}
+	msg := fmt.Sprintf("godojo failed during the %s phase with exit code %d", phase, code)
+	if d.lastCmd != "" {
+		msg += fmt.Sprintf(", last command run was:\n    %s", d.redactatron(d.lastCmd, d.redact))
+	}
+	if d.dbBackup != "" {
+		msg += fmt.Sprintf("\nThe existing database was backed up to %s", d.dbBackup)
+	}
+
+	if !d.quiet {
+		fmt.Printf("\n%s\nSee the logs in %s for details\n\n", msg, d.logLocation)
+	}
+	d.Error.Println(d.redactatron(msg, d.redact))
+	d.emit(event{
+		Type:     evSummary,
+		Phase:    phase,
+		Status:   "failed",
+		Message:  msg,
+		Command:  d.lastCmd,
+		ExitCode: code,
+		Version:  d.ver,
+		Backup:   d.dbBackup,
+		Archive:  d.archive,
+	})
+
+	d.releaseLocks()
+	if d.restoreDir != "" {
+		os.RemoveAll(d.restoreDir)
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Lock files prevent two godojo runs from installing into the same
+// Install.Root at the same time and from running the OS package manager
+// at the same time, even for different Install.Root directories
+const (
+	rootLockName = ".godojo.lock"
+	pkgLockPath  = "/var/lock/godojo-pkg.lock"
+)
+
+// Install phases that run the OS package manager
+var pkgMgrPhases = map[string]bool{
+	"bootstrap":     true,
+	"installerprep": true,
+	"installdb":     true,
+}
+
+// lockInfo holds the details written into a lock file
+type lockInfo struct {
+	pid     int
+	host    string
+	started string
+}
+
+// lockRoot takes a pointer to a DDConfig struct and takes the lock for the
+// configured Install.Root, exiting if another godojo run holds it
+func lockRoot(d *DDConfig) {
+	err := os.MkdirAll(d.conf.Install.Root, 0755)
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to create the install root %s, error was: %+v", d.conf.Install.Root, err))
+		d.exitWith(exitGeneral)
+	}
+	d.takeLock(filepath.Join(d.conf.Install.Root, rootLockName))
+}
+
+// withPkgLock runs the provided function while holding the global package
+// manager lock
+func (d *DDConfig) withPkgLock(f func()) {
+	err := os.MkdirAll(filepath.Dir(pkgLockPath), 0755)
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to create the directory for %s, error was: %+v", pkgLockPath, err))
+		d.exitWith(exitGeneral)
+	}
+	d.takeLock(pkgLockPath)
+	f()
+	d.releaseLock(pkgLockPath)
+}
+
+// takeLock creates the lock file at the provided path or exits with an
+// error explaining who holds the lock and how to break it if it's stale
+func (d *DDConfig) takeLock(p string) {
+	d.traceMsg(fmt.Sprintf("Taking lock %s", p))
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			d.lockHeld(p)
+		}
+		d.errorMsg(fmt.Sprintf("Unable to create lock file %s, error was: %+v", p, err))
+		d.exitWith(exitGeneral)
+	}
+
+	host, _ := os.Hostname()
+	_, err = fmt.Fprintf(f, "pid=%d\nhost=%s\nstarted=%s\n", os.Getpid(), host, time.Now().Format(time.RFC3339))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to write lock file %s, error was: %+v", p, err))
+		_ = os.Remove(p)
+		d.exitWith(exitGeneral)
+	}
+
+	// Make sure locks are removed if godojo is interrupted
+	d.lockMu.Lock()
+	if len(d.locks) == 0 {
+		d.releaseOnSignal()
+	}
+	d.locks = append(d.locks, p)
+	d.lockMu.Unlock()
+}
+
+// lockHeld reports the details of a lock held by another godojo run and exits
+func (d *DDConfig) lockHeld(p string) {
+	l := readLock(p)
+	msg := fmt.Sprintf("Another godojo run holds the lock file %s\n"+
+		"         PID: %d, host: %s, started: %s\n", p, l.pid, l.host, l.started)
+
+	host, _ := os.Hostname()
+	if l.host == host && !pidRunning(l.pid) {
+		msg += fmt.Sprintf("         PID %d is no longer running on this host so the lock is likely stale.\n", l.pid)
+	}
+	msg += "         If no other godojo run is in progress, remove the lock file with\n" +
+		fmt.Sprintf("           rm %s\n", p) +
+		"         and re-run godojo"
+
+	d.errorMsg(msg)
+	d.exitWith(exitLocked)
+}
+
+// releaseLock removes a lock file taken by this godojo run.  d.locks is
+// guarded by lockMu since the signal handler releases locks too.
+func (d *DDConfig) releaseLock(p string) {
+	d.lockMu.Lock()
+	defer d.lockMu.Unlock()
+	for i := range d.locks {
+		if d.locks[i] == p {
+			d.traceMsg(fmt.Sprintf("Releasing lock %s", p))
+			_ = os.Remove(p)
+			d.locks = append(d.locks[:i], d.locks[i+1:]...)
+			return
+		}
+	}
+}
+
+// releaseLocks removes all lock files taken by this godojo run
+func (d *DDConfig) releaseLocks() {
+	d.lockMu.Lock()
+	held := append([]string(nil), d.locks...)
+	d.lockMu.Unlock()
+	for i := len(held) - 1; i >= 0; i-- {
+		d.releaseLock(held[i])
+	}
+}
+
+// releaseOnSignal stops the running command then removes any held locks if
+// godojo is interrupted
+func (d *DDConfig) releaseOnSignal() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		s := <-sig
+		d.errorMsg(fmt.Sprintf("Received %s, stopping the install", s))
+		// Stop the running command first so nothing runs unlocked
+		d.stopChild(s)
+		d.exitWith(exitGeneral)
+	}()
+}
+
+// readLock returns the details in an existing lock file
+func readLock(p string) lockInfo {
+	l := lockInfo{host: "unknown", started: "unknown"}
+	f, err := os.Open(p)
+	if err != nil {
+		return l
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		switch k {
+		case "pid":
+			l.pid, _ = strconv.Atoi(v)
+		case "host":
+			l.host = v
+		case "started":
+			l.started = v
+		}
+	}
+
+	return l
+}
+
+// pidRunning returns true if a process with the provided PID exists
+func pidRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+
+	// EPERM means the process exists but is owned by another user
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// installPhase is a single step of a DefectDojo install.  Phase names match
+// the commandeer package labels used by the distros package where there is one.
+type installPhase struct {
+	name   string                         // Name used for --only, --skip, logs and JSON events
+	run    func(d *DDConfig, t *targetOS) // Does the work for this phase
+	prereq func(d *DDConfig) error        // Optional check that earlier phases were completed
+}
+
+// installPhases lists the install phases in the order they are run.
+// Determining the target OS always runs so isn't included here.
+var installPhases = []installPhase{
+	{name: "bootstrap", run: bootstrapInstall},
+	{name: "validpython", run: func(d *DDConfig, t *targetOS) { validPython(d) }},
+	{name: "download", run: func(d *DDConfig, t *targetOS) { downloadDojo(d) }},
+	{name: "installerprep", run: prepOSForDojo},
+	{name: "installdb", run: installDBForDojo},
+	{name: "prepdb", run: prepDBForDojo},
+	{name: "prepdjango", run: prepDjango, prereq: needSource},
+	{name: "createsettings", run: createSettings, prereq: needVirtualenv},
+	{name: "setupdojo", run: setupDefectDojo, prereq: needSettings},
+}
+
+// phaseNames returns the names of the install phases as a comma separated list
+func phaseNames() string {
+	n := make([]string, 0, len(installPhases))
+	for i := range installPhases {
+		n = append(n, installPhases[i].name)
+	}
+
+	return strings.Join(n, ",")
+}
+
+// selectPhases takes a pointer to a DDConfig struct and the values of --only
+// and --skip and records which install phases should be run
+func selectPhases(d *DDConfig, only string, skip string) {
+	d.runPhase = make(map[string]bool)
+	for i := range installPhases {
+		d.runPhase[installPhases[i].name] = (only == "")
+	}
+
+	for _, p := range splitPhases(d, only) {
+		d.runPhase[p] = true
+	}
+	for _, p := range splitPhases(d, skip) {
+		d.runPhase[p] = false
+	}
+}
+
+// splitPhases splits a comma separated list of phase names, exiting if any
+// of the names aren't valid install phases
+func splitPhases(d *DDConfig, l string) []string {
+	var ps []string
+	for _, p := range strings.Split(l, ",") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, ok := d.runPhase[p]; !ok {
+			fmt.Printf("Unknown install phase %s\nValid phases are: %s\n", p, phaseNames())
+			d.exitWith(exitConfig)
+		}
+		ps = append(ps, p)
+	}
+
+	return ps
+}
+
+// runPhases takes a pointer to a DDConfig struct and a pointer to a targetOS
+// struct and runs the selected install phases in order
+func runPhases(d *DDConfig, t *targetOS) {
+	for i := range installPhases {
+		p := installPhases[i]
+		if !d.runPhase[p.name] {
+			d.traceMsg(fmt.Sprintf("Skipping phase %s per --only or --skip", p.name))
+			d.statusMsg(fmt.Sprintf("Skipping the %s phase", p.name))
+			continue
+		}
+		if p.prereq != nil {
+			err := p.prereq(d)
+			if err != nil {
+				d.phase = p.name
+				d.errorMsg(fmt.Sprintf("Unable to run the %s phase: %+v\n"+
+					"         Run the phases it depends on first or adjust --only and --skip", p.name, err))
+				d.exitWith(exitConfig)
+			}
+		}
+		d.inPhase(p.name, func() { p.run(d, t) })
+	}
+}
+
+// needSource checks that the DefectDojo source has been downloaded
+func needSource(d *DDConfig) error {
+	src := filepath.Join(d.conf.Install.Root, d.conf.Install.Source)
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("DefectDojo source not found at %s, the download phase is required", src)
+	}
+
+	return nil
+}
+
+// needVirtualenv checks that the virtualenv for DefectDojo exists
+func needVirtualenv(d *DDConfig) error {
+	err := needSource(d)
+	if err != nil {
+		return err
+	}
+	venv := filepath.Join(d.conf.Install.Root, "bin", "activate")
+	if _, err := os.Stat(venv); err != nil {
+		return fmt.Errorf("virtualenv not found at %s, the prepdjango phase is required", venv)
+	}
+
+	return nil
+}
+
+// needSettings checks that settings for DefectDojo have been created
+func needSettings(d *DDConfig) error {
+	err := needVirtualenv(d)
+	if err != nil {
+		return err
+	}
+	env := filepath.Join(d.conf.Install.Root, d.conf.Install.Source, "dojo", "settings", ".env.prod")
+	if _, err := os.Stat(env); err != nil {
+		return fmt.Errorf("settings file not found at %s, the createsettings phase is required", env)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defectdojo/godojo/pkgmgr"
+)
+
+// manifestName is the file in Install.Root that records the OS packages
+// installed by godojo
+const manifestName = "godojo-manifest.json"
+
+// manifest is the contents of godojo-manifest.json
+type manifest struct {
+	Updated   string            `json:"updated"`
+	Version   string            `json:"godojo_version"`
+	OSUpdates string            `json:"os_updates"`
+	Pins      map[string]string `json:"pins,omitempty"`
+	Packages  []pkgmgr.Pkg      `json:"packages"`
+}
+
+// OS package update policies for Install.OSUpdates
+var osUpdatePolicies = map[string]bool{
+	"none":     true,
+	"security": true,
+	"all":      true,
+}
+
+// saneOSUpdates checks Install.OSUpdates, defaulting to all to match
+// earlier versions of godojo
+func saneOSUpdates(d *DDConfig) {
+	d.conf.Install.OSUpdates = strings.ToLower(strings.TrimSpace(d.conf.Install.OSUpdates))
+	if d.conf.Install.OSUpdates == "" {
+		d.conf.Install.OSUpdates = "all"
+	}
+	if !osUpdatePolicies[d.conf.Install.OSUpdates] {
+		d.errorMsg(fmt.Sprintf("Install.OSUpdates in dojoConfig.yml is %s, it must be none, security or all",
+			d.conf.Install.OSUpdates))
+		d.exitWith(exitConfig)
+	}
+}
+
+// pkgPolicy applies Install.OSUpdates to upgrades and Install.Pins to
+// installs, returning false if the command should be skipped
+func (d *DDConfig) pkgPolicy(op *pkgmgr.Op) bool {
+	switch op.Action {
+	case pkgmgr.Upgrade:
+		switch d.conf.Install.OSUpdates {
+		case "none":
+			d.traceMsg("Skipping OS package upgrades since Install.OSUpdates is none")
+			return false
+		case "security":
+			d.traceMsg("Only installing security updates since Install.OSUpdates is security")
+			op.Action = pkgmgr.Security
+		}
+	case pkgmgr.Install:
+		for i, p := range op.Pkgs {
+			if v, ok := d.conf.Install.Pins[p]; ok {
+				d.traceMsg(fmt.Sprintf("Installing %s version %s per Install.Pins", p, v))
+				op.Pkgs[i] = p + "=" + v
+				if d.pinsUsed == nil {
+					d.pinsUsed = make(map[string]bool)
+				}
+				d.pinsUsed[p] = true
+			}
+		}
+	}
+
+	return true
+}
+
+// unusedPins warns about packages in Install.Pins that no pkgmgr install
+// command matched.  Pins only apply to packages installed by name, not to
+// dependencies or packages installed by a script.
+func unusedPins(d *DDConfig) {
+	var unused []string
+	for p := range d.conf.Install.Pins {
+		if !d.pinsUsed[p] {
+			unused = append(unused, p)
+		}
+	}
+	sort.Strings(unused)
+	for _, p := range unused {
+		d.warnMsg(fmt.Sprintf("Install.Pins has %s but godojo didn't install a package by that name, the pin was not applied", p))
+	}
+}
+
+// sendPkgCmd runs a package manager command from a command pack with the
+// pkgmgr package and records any packages it installed in the manifest.  The
+// timeout applies to each package manager run, including retries.
+func sendPkgCmd(d *DDConfig, op pkgmgr.Op, hard bool, timeout time.Duration) error {
+	if !d.pkgPolicy(&op) {
+		return nil
+	}
+
+	opts := pkgmgr.DefaultOptions()
+	opts.Trace = d.traceMsg
+	run := func(cmd string) (string, error) { return d.pkgRunner(cmd, timeout) }
+	pkgs, err := pkgmgr.Run(op, run, opts)
+	if errors.Is(err, pkgmgr.ErrNoSecurity) {
+		d.warnMsg(fmt.Sprintf("%s can't install only security updates, skipping OS package upgrades", op.Manager))
+		err = nil
+	}
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("%s - Failed to run %s %s, error was: %+v", timeStamp(), op.Manager, op.Action, err))
+		if hard {
+			// Exit on hard aka fatal errors
+			if errors.Is(err, pkgmgr.ErrLocked) {
+				d.exitWith(exitLocked)
+			}
+			d.exitWith(d.cmdExitCode())
+		}
+		return err
+	}
+	d.recordPkgs(pkgs)
+
+	return nil
+}
+
+// pkgRunner runs commands for the pkgmgr package, logging them like sendCmd
+func (d *DDConfig) pkgRunner(cmd string, timeout time.Duration) (string, error) {
+	d.lastCmd = cmd
+	d.cmdLogger.Printf("[godojo] # %s\n", d.redactatron(cmd, d.redact))
+	var buf bytes.Buffer
+	runCmd := shellCmd(d, cmd)
+	runCmd.Stdout = &buf
+	runCmd.Stderr = &buf
+	err := runShell(d, runCmd, timeout)
+	out := buf.Bytes()
+	d.cmdLogger.Printf("%s\n", string(out))
+
+	// Show the output if --follow was used
+	if d.follow && !d.quiet {
+		console := newLineRedactor(d, os.Stdout)
+		_, _ = console.Write(out)
+		console.Flush()
+	}
+
+	return string(out), err
+}
+
+// recordPkgs adds the provided packages and the OS package policy to the
+// manifest in Install.Root
+func (d *DDConfig) recordPkgs(pkgs []pkgmgr.Pkg) {
+	p := filepath.Join(d.conf.Install.Root, manifestName)
+	m := manifest{}
+	b, err := os.ReadFile(p)
+	if err == nil {
+		err = json.Unmarshal(b, &m)
+		if err != nil {
+			d.warnMsg(fmt.Sprintf("Unable to parse %s, starting a new manifest. Error was: %+v", p, err))
+			m = manifest{}
+		}
+	}
+
+	// Replace earlier entries for the same package
+	for _, pkg := range pkgs {
+		d.traceMsg(fmt.Sprintf("Installed %s %s with %s", pkg.Name, pkg.Version, pkg.Manager))
+		found := false
+		for i := range m.Packages {
+			if m.Packages[i].Name == pkg.Name && m.Packages[i].Manager == pkg.Manager {
+				m.Packages[i] = pkg
+				found = true
+			}
+		}
+		if !found {
+			m.Packages = append(m.Packages, pkg)
+		}
+	}
+	m.Updated = time.Now().Format(time.RFC3339)
+	m.Version = d.ver
+	m.OSUpdates = d.conf.Install.OSUpdates
+	m.Pins = d.conf.Install.Pins
+
+	b, err = json.MarshalIndent(m, "", "  ")
+	if err == nil {
+		err = os.WriteFile(p, b, 0644)
+	}
+	if err != nil {
+		d.warnMsg(fmt.Sprintf("Unable to write the package manifest %s, error was: %+v", p, err))
+	}
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"os/user"
 	"strconv"
@@ -24,13 +23,16 @@ func prepInstaller(d *DDConfig) {
 	if d.defInstall {
 		// Set config options based on embedded default config
 		defaultConfig(d)
+	} else if d.subCmd == "restore" {
+		// Unpack the archive, it may have the config to use
+		readRestoreConfig(d)
 	} else {
 		// Read dojoConfig.yml file
 		readConfigFile(d)
 	}
 
 	// Read in any environmental variables
-	readEnvVars(&d.conf)
+	readEnvVars(d)
 
 	// Write final install configuration to a file
 	writeFinalConfig(d)
@@ -44,6 +46,9 @@ func prepInstaller(d *DDConfig) {
 	// Check that configured DB configuration is sane
 	saneDBConfig(d)
 
+	// Check the OS package update policy
+	saneOSUpdates(d)
+
 	// Logging is setup, start using statusMsg and errorMsg functions for output
 	d.traceMsg("Logging established, trace log begins here")
 	d.sectionMsg("Starting the dojo install at " + time.Now().Format("Mon Jan 2, 2006 15:04:05 MST"))
@@ -65,7 +70,7 @@ func defaultConfig(d *DDConfig) {
 	if err != nil {
 		fmt.Println("Unable to determine current working directory, exiting...")
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitGeneral)
 	}
 	err = os.Remove(path + "/" + d.cf)
 	if err != nil {
@@ -97,7 +102,9 @@ func defaultConfig(d *DDConfig) {
 // overrides any options set in the configuration file. These variables
 // are used to supply either install-time configurations or provide values
 // that are used in DefectDojo's settings.py configuration file
-func readEnvVars(gdConf *dojoConfig) {
+func readEnvVars(d *DDConfig) {
+	gdConf := &d.conf
+
 	// Env variables pulled from repo. Add newly supported env vars below and
 	// to the switch statement below after the for that ranges over overrides
 	// TODO: Add non-setting.py ENV vars like DD_SourcCommit
@@ -245,8 +252,8 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_CELERY_BROKER_PATH":
 			gdConf.Settings.CeleryBrokerPath = v
 		case "DD_CELERY_BROKER_PORT":
-			port := convInt(v, "DD_CELERY_BROKER_PORT provided via environmental variable isn't a valid port number")
-			intLessThan(port, 65535, "DD_CELERY_BROKER_PORT provided via environmental variable is too large")
+			port := convInt(d, v, "DD_CELERY_BROKER_PORT provided via environmental variable isn't a valid port number")
+			intLessThan(d, port, 65535, "DD_CELERY_BROKER_PORT provided via environmental variable is too large")
 			gdConf.Settings.CeleryBrokerPort = port
 		case "DD_CELERY_BROKER_SCHEME":
 			gdConf.Settings.CeleryBrokerScheme = v
@@ -259,17 +266,17 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_CELERY_RESULT_BACKEND":
 			gdConf.Settings.CeleryResultBackend = v
 		case "DD_CELERY_RESULT_EXPIRES":
-			gdConf.Settings.CeleryResultExpires = convInt(v, "DD_CELERY_RESULT_EXPIRES provided via environmental variable isn't a valid number")
+			gdConf.Settings.CeleryResultExpires = convInt(d, v, "DD_CELERY_RESULT_EXPIRES provided via environmental variable isn't a valid number")
 		case "DD_CELERY_TASK_IGNORE_RESULT":
-			gdConf.Settings.CeleryTaskIgnoreResult = convBool(v, "DD_CELERY_TASK_IGNORE_RESULT environmental variable was not a boolean.")
+			gdConf.Settings.CeleryTaskIgnoreResult = convBool(d, v, "DD_CELERY_TASK_IGNORE_RESULT environmental variable was not a boolean.")
 		case "DD_CELERY_TASK_SERIALIZER":
 			gdConf.Settings.CeleryTaskSerializer = v
 		case "DD_CREDENTIAL_AES_256_KEY":
 			gdConf.Settings.CredentialAES256Key = v
 		case "DD_CSRF_COOKIE_HTTPONLY":
-			gdConf.Settings.CSRFCookieHTTPOnly = convBool(v, "DD_CSRF_COOKIE_HTTPONLY environmental variable was not a boolean.")
+			gdConf.Settings.CSRFCookieHTTPOnly = convBool(d, v, "DD_CSRF_COOKIE_HTTPONLY environmental variable was not a boolean.")
 		case "DD_CSRF_COOKIE_SECURE":
-			gdConf.Settings.CSRFCookieSecure = convBool(v, "DD_CSRF_COOKIE_SECURE environmental variable was not a boolean.")
+			gdConf.Settings.CSRFCookieSecure = convBool(d, v, "DD_CSRF_COOKIE_SECURE environmental variable was not a boolean.")
 		case "DD_DATABASE_ENGINE":
 			gdConf.Settings.DatabaseEngine = v
 		case "DD_DATABASE_HOST":
@@ -287,11 +294,11 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_DATABASE_USER":
 			gdConf.Settings.DatabaseUser = v
 		case "DD_DATA_UPLOAD_MAX_MEMORY_SIZE":
-			gdConf.Settings.DataUploadMaxMemorySize = convInt(v, "DD_DATA_UPLOAD_MAX_MEMORY_SIZE provided via environmental variable isn't a valid number")
+			gdConf.Settings.DataUploadMaxMemorySize = convInt(d, v, "DD_DATA_UPLOAD_MAX_MEMORY_SIZE provided via environmental variable isn't a valid number")
 		case "DD_DEBUG":
-			gdConf.Settings.Debug = convBool(v, "DD_DEBUG environmental variable was not a boolean.")
+			gdConf.Settings.Debug = convBool(d, v, "DD_DEBUG environmental variable was not a boolean.")
 		case "DD_DJANGO_ADMIN_ENABLED":
-			gdConf.Settings.DjangoAdminEnabled = convBool(v, "DD_DJANGO_ADMIN_ENABLED environmental variable was not a boolean.")
+			gdConf.Settings.DjangoAdminEnabled = convBool(d, v, "DD_DJANGO_ADMIN_ENABLED environmental variable was not a boolean.")
 		case "DD_EMAIL_URL":
 			gdConf.Settings.EmailURL = v
 		case "DD_ENV":
@@ -299,7 +306,7 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_ENV_PATH":
 			gdConf.Settings.EnvPath = v
 		case "DD_FORCE_LOWERCASE_TAGS":
-			gdConf.Settings.ForceLowercaseTags = convBool(v, "DD_FORCE_LOWERCASE_TAGS environmental variable was not a boolean.")
+			gdConf.Settings.ForceLowercaseTags = convBool(d, v, "DD_FORCE_LOWERCASE_TAGS environmental variable was not a boolean.")
 		case "DD_HOST":
 			gdConf.Settings.Host = v
 		case "DD_INITIALIZE":
@@ -312,7 +319,7 @@ func readEnvVars(gdConf *dojoConfig) {
 			gdConf.Settings.LoginRedirectURL = v
 		case "DD_MAX_TAG_LENGTH":
 			// TODO: Look up maximum tag length in data model and check for that too
-			gdConf.Settings.MaxTagLength = convInt(v, "DD_MAX_TAG_LENGTH provided via environmental variable isn't a valid number")
+			gdConf.Settings.MaxTagLength = convInt(d, v, "DD_MAX_TAG_LENGTH provided via environmental variable isn't a valid number")
 		case "DD_MEDIA_ROOT":
 			gdConf.Settings.MediaRoot = v
 		case "DD_MEDIA_URL":
@@ -332,23 +339,23 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_SECRET_KEY":
 			gdConf.Settings.SecretKey = v
 		case "DD_SECURE_BROWSER_XSS_FILTER":
-			gdConf.Settings.SecureBrowserXSSFilter = convBool(v, "DD_SECURE_BROWSER_XSS_FILTER environmental variable was not a boolean.")
+			gdConf.Settings.SecureBrowserXSSFilter = convBool(d, v, "DD_SECURE_BROWSER_XSS_FILTER environmental variable was not a boolean.")
 		case "DD_SECURE_CONTENT_TYPE_NOSNIFF":
 			gdConf.Settings.SecureContentTypeNosniff = v
 		case "DD_SECURE_HSTS_INCLUDE_SUBDOMAINS":
-			gdConf.Settings.SecureHSTSIncludeSubdomains = convBool(v, "DD_SECURE_HSTS_INCLUDE_SUBDOMAINS environmental variable was not a boolean.")
+			gdConf.Settings.SecureHSTSIncludeSubdomains = convBool(d, v, "DD_SECURE_HSTS_INCLUDE_SUBDOMAINS environmental variable was not a boolean.")
 		case "DD_SECURE_HSTS_SECONDS":
-			gdConf.Settings.SecureHSTSSeconds = convInt(v, "DD_SECURE_HSTS_SECONDS provided via environmental variable isn't a valid number")
+			gdConf.Settings.SecureHSTSSeconds = convInt(d, v, "DD_SECURE_HSTS_SECONDS provided via environmental variable isn't a valid number")
 		case "DD_SECURE_PROXY_SSL_HEADER":
-			gdConf.Settings.SecureProxySSLHeader = convBool(v, "DD_SECURE_PROXY_SSL_HEADER environmental variable was not a boolean.")
+			gdConf.Settings.SecureProxySSLHeader = convBool(d, v, "DD_SECURE_PROXY_SSL_HEADER environmental variable was not a boolean.")
 		case "DD_SECURE_SSL_REDIRECT":
-			gdConf.Settings.SecureSSLRedirect = convBool(v, "DD_SECURE_SSL_REDIRECT environmental variable was not a boolean.")
+			gdConf.Settings.SecureSSLRedirect = convBool(d, v, "DD_SECURE_SSL_REDIRECT environmental variable was not a boolean.")
 		case "DD_SESSION_COOKIE_HTTPONLY":
-			gdConf.Settings.SessionCookieHTTPOnly = convBool(v, "DD_SESSION_COOKIE_HTTPONLY environmental variable was not a boolean.")
+			gdConf.Settings.SessionCookieHTTPOnly = convBool(d, v, "DD_SESSION_COOKIE_HTTPONLY environmental variable was not a boolean.")
 		case "DD_SESSION_COOKIE_SECURE":
-			gdConf.Settings.SessionCookieSecure = convBool(v, "DD_SESSION_COOKIE_SECURE environmental variable was not a boolean.")
+			gdConf.Settings.SessionCookieSecure = convBool(d, v, "DD_SESSION_COOKIE_SECURE environmental variable was not a boolean.")
 		case "DD_SITE_ID":
-			gdConf.Settings.SiteID = convInt(v, "DD_SITE_ID provided via environmental variable isn't a valid number")
+			gdConf.Settings.SiteID = convInt(d, v, "DD_SITE_ID provided via environmental variable isn't a valid number")
 		case "DD_SOCIAL_AUTH_AZUREAD_TENANT_OAUTH2_ENABLED":
 			gdConf.Settings.SocialAuthAzureadTenantOauth2Enabled = v
 		case "DD_SOCIAL_AUTH_AZUREAD_TENANT_OAUTH2_KEY":
@@ -386,15 +393,15 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_TIME_ZONE":
 			gdConf.Settings.TimeZone = v
 		case "DD_TRACK_MIGRATIONS":
-			gdConf.Settings.TrackMigrations = convBool(v, "DD_TRACK_MIGRATIONS environmental variable was not a boolean.")
+			gdConf.Settings.TrackMigrations = convBool(d, v, "DD_TRACK_MIGRATIONS environmental variable was not a boolean.")
 		case "DD_URL_PREFIX":
 			gdConf.Settings.URLPrefix = v
 		case "DD_USE_I18N":
-			gdConf.Settings.UseI18N = convBool(v, "DD_USE_I18N environmental variable was not a boolean.")
+			gdConf.Settings.UseI18N = convBool(d, v, "DD_USE_I18N environmental variable was not a boolean.")
 		case "DD_USE_L10N":
-			gdConf.Settings.UseL10N = convBool(v, "DD_USE_L10N environmental variable was not a boolean.")
+			gdConf.Settings.UseL10N = convBool(d, v, "DD_USE_L10N environmental variable was not a boolean.")
 		case "DD_USE_TZ":
-			gdConf.Settings.UseTZ = convBool(v, "DD_USE_TZ environmental variable was not a boolean.")
+			gdConf.Settings.UseTZ = convBool(d, v, "DD_USE_TZ environmental variable was not a boolean.")
 		case "DD_UUID":
 			gdConf.Settings.UUID = v
 		case "DD_UWSGI_ENDPOINT":
@@ -408,7 +415,7 @@ func readEnvVars(gdConf *dojoConfig) {
 		case "DD_UWSGI_PORT":
 			gdConf.Settings.UwsgiPort = v
 		case "DD_WHITENOISE":
-			gdConf.Settings.Whitenoise = convBool(v, "DD_WHITENOISE environmental variable was not a boolean.")
+			gdConf.Settings.Whitenoise = convBool(d, v, "DD_WHITENOISE environmental variable was not a boolean.")
 		case "DD_WKHTMLTOPDF":
 			gdConf.Settings.Wkhtmltopdf = v
 		case "DOJO_ADMIN_USER":
@@ -419,44 +426,51 @@ func readEnvVars(gdConf *dojoConfig) {
 
 }
 
-func convInt(i string, s string) int {
+// convInt converts an environmental variable to an int, exiting with
+// exitConfig and the error message in s if it isn't one
+func convInt(d *DDConfig, i string, s string) int {
 	convI, err := strconv.Atoi(i)
 	if err != nil {
 		fmt.Println("ERROR:")
 		fmt.Printf("  %s\n", s)
 		fmt.Printf("  Error was: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitConfig)
 	}
 	return convI
 }
 
-func intLessThan(i int, max int, s string) {
+// intLessThan exits with exitConfig and the error message in s if i is
+// larger than max
+func intLessThan(d *DDConfig, i int, max int, s string) {
 	if i > max {
 		fmt.Println("ERROR:")
 		fmt.Printf("  %s\n", s)
-		os.Exit(1)
+		d.exitWith(exitConfig)
 	}
 }
 
-func convBool(b string, s string) bool {
+// convBool converts an environmental variable to a bool, exiting with
+// exitConfig and the error message in s if it isn't one
+func convBool(d *DDConfig, b string, s string) bool {
 	res, err := strconv.ParseBool(b)
 	if err != nil {
 		fmt.Println("ERROR:")
 		fmt.Printf("  %s\n", s)
 		fmt.Println("  Valid values are 1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False.")
 		fmt.Printf("  Error was: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitConfig)
 	}
 	return res
 }
 
 // checkUserPrivs takes a pointer to DDConfig struct and verifies that the
 // user running godojo has sufficient privileges to complete the install and
-// exits with a 1 if privileges are lacking
+// exits with exitPrivs if privileges are lacking or can't be determined
 func checkUserPrivs(d *DDConfig) {
 	usr, err := user.Current()
 	if err != nil {
-		log.Fatal(err)
+		d.errorMsg(fmt.Sprintf("Unable to determine the user running godojo, error was: %+v", err))
+		d.exitWith(exitPrivs)
 	}
 	if usr.Uid != "0" && !d.conf.Options.UsrInst {
 		fmt.Println("")
@@ -464,6 +478,6 @@ func checkUserPrivs(d *DDConfig) {
 		fmt.Println("  ERROR: This program must be run as root or with sudo\n  Please correct and run installer again")
 		fmt.Println("##############################################################################")
 		fmt.Println("")
-		os.Exit(1)
+		d.exitWith(exitPrivs)
 	}
 }
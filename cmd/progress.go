@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/defectdojo/godojo/pkgmgr"
+	c "github.com/mtesauro/commandeer"
+)
+
+// Longest command summary shown in the progress display
+const maxStepLen = 70
+
+// isTerminal returns true if the provided file is attached to a terminal
+// and false for pipes, files and other non-TTY outputs like CI logs
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// startSpin starts the progress spinner with the provided prefix but only
+// when output is going to a terminal and isn't suppressed or being streamed
+func (d *DDConfig) startSpin(prefix string) {
+	if d.quiet || d.follow || !d.isTTY {
+		return
+	}
+	d.spin = spinner.New(spinner.CharSets[34], 100*time.Millisecond)
+	d.spin.Prefix = prefix
+	d.spin.Start()
+}
+
+// setSpin updates the prefix of a running spinner
+func (d *DDConfig) setSpin(prefix string) {
+	if d.spin == nil || !d.spin.Active() {
+		return
+	}
+	d.spin.Lock()
+	d.spin.Prefix = prefix
+	d.spin.Unlock()
+}
+
+// stopSpin stops the progress spinner if one is running
+func (d *DDConfig) stopSpin() {
+	if d.spin == nil {
+		return
+	}
+	d.spin.Stop()
+}
+
+// runPhaseCmds takes a pointer to a DDConfig struct, a label for the
+// commands, a prefix for the spinner and the commands and runs them one at
+// a time showing "step x/y" with elapsed time for each command.  If --follow
+// is set, the output of each command is streamed as it runs.  Command events
+// carry the enclosing phase from inPhase, the label is only for display.
+func runPhaseCmds(d *DDConfig, label string, prefix string, tCmds []c.SingleCmd) {
+	total := len(tCmds)
+	if d.follow && !d.quiet {
+		fmt.Printf("==> Phase %s: %d command(s) to run\n", label, total)
+	}
+
+	d.startSpin(prefix)
+	for i := range tCmds {
+		step := fmt.Sprintf("step %d/%d: %s", i+1, total, d.stepSummary(tCmds[i].Cmd))
+		d.showStep(prefix, step)
+
+		d.emit(event{Type: evCmdStart, Command: tCmds[i].Cmd, Step: i + 1, Steps: total})
+		start := time.Now()
+		var err error
+		if op, ok := pkgmgr.Parse(tCmds[i].Cmd); ok {
+			err = sendPkgCmd(d, op, tCmds[i].Hard, tCmds[i].Timeout)
+		} else {
+			err = sendCmd(d,
+				d.cmdLogger,
+				tCmds[i].Cmd,
+				tCmds[i].Errmsg,
+				tCmds[i].Hard,
+				tCmds[i].Timeout)
+		}
+		d.emit(event{Type: evCmdFinish, Command: tCmds[i].Cmd, Step: i + 1, Steps: total,
+			Status: cmdStatus(err), Elapsed: time.Since(start).Seconds()})
+		d.stepDone(step, time.Since(start))
+	}
+	d.stopSpin()
+}
+
+// cmdStatus returns the status of a command for JSON events
+func cmdStatus(err error) string {
+	if err != nil {
+		return "failed"
+	}
+
+	return "ok"
+}
+
+// showStep displays the command about to be run based on the type of output
+func (d *DDConfig) showStep(prefix string, step string) {
+	d.traceMsg(fmt.Sprintf("Starting %s", step))
+	switch {
+	case d.quiet:
+		return
+	case d.follow:
+		fmt.Printf("--> %s\n", step)
+	case d.isTTY:
+		d.setSpin(prefix + " " + step + " ")
+	default:
+		// Non-TTY output like CI logs get one line per command, no spinner
+		fmt.Printf("  %s\n", step)
+	}
+}
+
+// stepDone reports the elapsed time for a command that has completed
+func (d *DDConfig) stepDone(step string, el time.Duration) {
+	d.traceMsg(fmt.Sprintf("Finished %s in %s", step, roundDur(el)))
+	if d.quiet {
+		return
+	}
+	if d.isTTY && !d.follow {
+		// Print over the spinner line then let the spinner continue below
+		d.spin.Lock()
+		fmt.Fprintf(d.spin.Writer, "\r\033[K  %s (%s)\n", step, roundDur(el))
+		d.spin.Unlock()
+		return
+	}
+	fmt.Printf("  %s finished in %s\n", step, roundDur(el))
+}
+
+// stepSummary shortens an OS command into something readable for the
+// progress display e.g. /opt/dojo/bin/pip3 install -r /opt/dojo/django-DefectDojo/requirements.txt
+// becomes pip3 install -r requirements.txt
+func (d *DDConfig) stepSummary(cmd string) string {
+	// Only the last command in a chain like "cd foo && source bar && python3 baz" matters
+	parts := strings.Split(cmd, "&&")
+	last := strings.TrimSpace(parts[len(parts)-1])
+
+	// Drop the directories from any paths
+	words := strings.Fields(last)
+	for i := range words {
+		if strings.Contains(words[i], "/") && !strings.Contains(words[i], "://") {
+			words[i] = path.Base(strings.TrimRight(words[i], "/"))
+		}
+	}
+	sum := d.redactatron(strings.Join(words, " "), d.redact)
+
+	if len(sum) > maxStepLen {
+		sum = sum[:maxStepLen-3] + "..."
+	}
+
+	return sum
+}
+
+// roundDur rounds a duration to something sensible to display
+func roundDur(el time.Duration) time.Duration {
+	if el < time.Second {
+		return el.Round(time.Millisecond)
+	}
+
+	return el.Round(100 * time.Millisecond)
+}
+
+// lineRedactor is an io.Writer that buffers output into lines and redacts
+// sensitive strings before writing complete lines to the wrapped writer
+type lineRedactor struct {
+	d   *DDConfig
+	out io.Writer
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func newLineRedactor(d *DDConfig, out io.Writer) *lineRedactor {
+	return &lineRedactor{d: d, out: out}
+}
+
+func (l *lineRedactor) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf.Write(p)
+	for {
+		line, err := l.buf.ReadString('\n')
+		if err != nil {
+			// Partial line, put it back until the rest arrives
+			l.buf.Reset()
+			l.buf.WriteString(line)
+			break
+		}
+		_, werr := io.WriteString(l.out, l.d.redactatron(line, l.d.redact))
+		if werr != nil {
+			return len(p), werr
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any remaining partial line
+func (l *lineRedactor) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buf.Len() > 0 {
+		_, _ = io.WriteString(l.out, l.d.redactatron(l.buf.String(), l.d.redact)+"\n")
+		l.buf.Reset()
+	}
+}
@@ -14,6 +14,10 @@ func (d *DDConfig) redactatron(l string, on bool) string {
 		clean := l
 		r := "[~REDACTED~]"
 		for i := range d.sensStr {
+			// Empty strings would match between every character
+			if d.sensStr[i] == "" {
+				continue
+			}
 			// Replacement will only be for redacted values
 			clean = strings.Replace(clean, d.sensStr[i], r, -1)
 		}
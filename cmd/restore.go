@@ -0,0 +1,489 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/mtesauro/commandeer"
+	"github.com/spf13/viper"
+)
+
+// Handles godojo restore which rebuilds a DefectDojo instance from an
+// archive written by godojo backup
+
+// readRestoreConfig takes a pointer to a DDConfig struct and reads the config
+// for the restore.  A dojoConfig.yml in the current directory is used if
+// there is one, otherwise the instance is restored with the config it was
+// backed up with.  The archive is only unpacked and verified by runRestore
+// once the privileges are checked and Install.Root is locked.
+func readRestoreConfig(d *DDConfig) {
+	if _, err := os.Stat(d.cf); err == nil {
+		readConfigFile(d)
+		return
+	}
+	b, err := archivedConfig(d.subArgs[0])
+	if err != nil {
+		fmt.Println("")
+		fmt.Printf("Unable to restore from %s\n", d.subArgs[0])
+		fmt.Printf("Error was: %v\n", err)
+		d.exitWith(exitBackup)
+	}
+	viper.SetConfigType("yaml")
+	err = viper.ReadConfig(bytes.NewReader(b))
+	if err == nil {
+		err = viper.Unmarshal(&d.conf)
+	}
+	if err != nil {
+		fmt.Println("")
+		fmt.Println("Unable to read the config from the backup archive, exiting restore")
+		fmt.Printf("Error was: %v\n", err)
+		d.exitWith(exitConfig)
+	}
+}
+
+// runRestore takes a pointer to a DDConfig struct and restores the backup
+// archive, installing DefectDojo first if it isn't already installed
+func runRestore(d *DDConfig) {
+	// Print the install banner
+	if !(d.quiet || d.conf.Options.Embd) {
+		d.dojoBanner()
+	}
+	d.cmdLogger = setCmdLogging(d)
+	embdCk(d)
+	lockRoot(d)
+
+	dir, m, err := unpackArchive(d, d.subArgs[0])
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to restore from %s: %+v", d.subArgs[0], err))
+		d.exitWith(exitBackup)
+	}
+	d.restoreDir = dir
+	d.restoreFrom = m
+
+	var osTarget targetOS
+	d.inPhase("checkos", func() { osTarget = checkOS(d) })
+
+	if dbFamily(m.Engine) != dbFamily(d.conf.Install.DB.Engine) {
+		d.errorMsg(fmt.Sprintf("The backup has a %s database which can't be restored to %s, set Install.DB.Engine to %s",
+			m.Engine, d.conf.Install.DB.Engine, m.Engine))
+		d.exitWith(exitConfig)
+	}
+
+	// The restored database replaces the existing one, backupBeforeDrop
+	// keeps a copy of it first
+	d.conf.Install.DB.Drop = true
+
+	// Migrations only go forward so the DefectDojo restored to can't be older
+	// than the backup.  A fresh host gets a full install of the configured
+	// version so check that before installing it.
+	var migrate bool
+	if needSettings(d) != nil {
+		to := d.conf.Install.Version
+		if d.conf.Install.SourceInstall {
+			// The version of a branch isn't known until it's checked out, always migrate
+			to = ""
+		}
+		migrate, err = needMigrate(m.DojoVersion, to)
+		if err != nil {
+			d.errorMsg(fmt.Sprintf("%+v", err))
+			d.exitWith(exitConfig)
+		}
+
+		d.statusMsg(fmt.Sprintf("DefectDojo isn't installed in %s, installing it before restoring", d.conf.Install.Root))
+		selectPhases(d, "", "")
+		runPhases(d, &osTarget)
+		unusedPins(d)
+		// Nothing to keep in the database the install just created
+		d.freshDB = true
+	} else {
+		migrate, err = needMigrate(m.DojoVersion, dojoVersion(d))
+		if err != nil {
+			d.errorMsg(fmt.Sprintf("%+v", err))
+			d.exitWith(exitConfig)
+		}
+	}
+
+	d.inPhase("prepdb", func() { prepDBForDojo(d, &osTarget) })
+	d.inPhase("restore", func() { restoreInstance(d) })
+	if migrate {
+		d.inPhase("migrate", func() {
+			d.sectionMsg(fmt.Sprintf("Migrating the database from DefectDojo %s to %s", m.DojoVersion, dojoVersion(d)))
+			tCmds := []c.SingleCmd{{
+				Cmd:    "cd {conf.Install.Root}/django-DefectDojo && source ../bin/activate && python3 manage.py migrate --noinput",
+				Errmsg: "Failed during database migrate",
+				Hard:   true,
+			}}
+			d.injectConfigVals(tCmds)
+			runPhaseCmds(d, "migrate", "Migrating the database...", tCmds)
+		})
+	}
+
+	os.RemoveAll(d.restoreDir)
+	d.statusMsg(fmt.Sprintf("\nSuccessfully restored DefectDojo from %s using godojo version %+v", d.subArgs[0], d.ver))
+	if d.dbBackup != "" {
+		d.statusMsg(fmt.Sprintf("The database replaced by the restore was backed up to %s", d.dbBackup))
+	}
+	d.releaseLocks()
+	d.summary("ok", "DefectDojo restored")
+}
+
+// restoreInstance loads the database dump, settings and media from the
+// unpacked archive
+func restoreInstance(d *DDConfig) {
+	d.sectionMsg(fmt.Sprintf("Restoring DefectDojo from %s", d.subArgs[0]))
+
+	d.statusMsg(fmt.Sprintf("Loading the backup into %s database %s", d.conf.Install.DB.Engine, d.conf.Install.DB.Name))
+	f, err := os.Open(filepath.Join(d.restoreDir, filepath.FromSlash(dbArchivePath(d.restoreFrom.Engine))))
+	if err == nil {
+		creds := map[string]string{"user": d.conf.Install.DB.User, "pass": d.conf.Install.DB.Pass}
+		err = loadDB(d, creds, f)
+		f.Close()
+	}
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("Unable to load the database from the backup: %+v", err))
+		d.exitWith(exitDBPrep)
+	}
+
+	d.statusMsg("Restoring settings")
+	err = restoreSettings(d)
+	if err == nil {
+		err = restoreMedia(d)
+	}
+	if err != nil {
+		d.errorMsg(fmt.Sprintf("%+v", err))
+		d.exitWith(exitBackup)
+	}
+
+	tCmds := []c.SingleCmd{{
+		Cmd:    "chown -R {conf.Install.OS.User}.{conf.Install.OS.Group} {conf.Install.Root}",
+		Errmsg: "Unable to change ownership of the DefectDojo directory",
+		Hard:   true,
+	}}
+	if !strings.HasPrefix(mediaDir(d), d.conf.Install.Root+"/") {
+		tCmds = append(tCmds, c.SingleCmd{
+			Cmd:    "chown -R {conf.Install.OS.User}.{conf.Install.OS.Group} " + mediaDir(d),
+			Errmsg: "Unable to change ownership of the media directory",
+			Hard:   true,
+		})
+	}
+	d.injectConfigVals(tCmds)
+	runPhaseCmds(d, "restore", "Setting ownership of restored files...", tCmds)
+	d.statusMsg("Restoring DefectDojo complete")
+}
+
+// restoreSettings copies .env.prod and local_settings.py from the backup.
+// DD_DATABASE_URL is set for the database being restored to, which may
+// not be the one that was backed up.
+func restoreSettings(d *DDConfig) error {
+	for _, s := range settingsFiles {
+		b, err := os.ReadFile(filepath.Join(d.restoreDir, archSettings, s))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Unable to read %s from the backup: %w", s, err)
+		}
+		if s == ".env.prod" {
+			b = setEnvLine(b, "DD_DATABASE_URL", databaseURL(d))
+		}
+		err = os.WriteFile(filepath.Join(settingsDir(d), s), b, 0640)
+		if err != nil {
+			return fmt.Errorf("Unable to restore %s: %w", s, err)
+		}
+	}
+
+	return nil
+}
+
+// setEnvLine replaces the value of key in the contents of an env file
+func setEnvLine(b []byte, key string, val string) []byte {
+	lines := strings.Split(string(b), "\n")
+	for i := range lines {
+		if strings.HasPrefix(lines[i], key+"=") {
+			lines[i] = key + "=" + val
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// restoreMedia copies uploaded files from the backup, moving any existing
+// media directory aside rather than removing it
+func restoreMedia(d *DDConfig) error {
+	src := filepath.Join(d.restoreDir, archMedia)
+	if _, err := os.Stat(src); err != nil {
+		d.traceMsg("No media in the backup to restore")
+		return nil
+	}
+	media := mediaDir(d)
+	if _, err := os.Stat(media); err == nil {
+		old := media + ".pre-restore-" + time.Now().Format("20060102-150405")
+		err = os.Rename(media, old)
+		if err != nil {
+			return fmt.Errorf("Unable to move the existing media directory aside: %w", err)
+		}
+		d.statusMsg(fmt.Sprintf("Moved the existing media directory to %s", old))
+	}
+
+	d.statusMsg(fmt.Sprintf("Restoring uploaded files to %s", media))
+	return filepath.WalkDir(src, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			return os.MkdirAll(filepath.Join(media, rel), 0755)
+		}
+		return copyFile(p, filepath.Join(media, rel), 0644)
+	})
+}
+
+// copyFile copies the file at src to dst with the permissions in mode
+func copyFile(src string, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// unpackArchive extracts a backup archive into a new directory under the
+// backup directory and checks the files against the manifest
+func unpackArchive(d *DDConfig, p string) (string, backupManifest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", backupManifest{}, err
+	}
+	defer f.Close()
+
+	// The archive has secrets so unpack it in the backup directory which
+	// only root can read, not next to an archive that may be read-only
+	err = os.MkdirAll(backupDir(d), 0700)
+	if err != nil {
+		return "", backupManifest{}, err
+	}
+	dir, err := os.MkdirTemp(backupDir(d), ".godojo-restore-")
+	if err != nil {
+		return "", backupManifest{}, err
+	}
+	got, err := extractArchive(f, dir)
+	var m backupManifest
+	if err == nil {
+		m, err = verifyManifest(dir, got)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", backupManifest{}, err
+	}
+
+	return dir, m, nil
+}
+
+// archivedConfig returns the runtime config from a backup archive without
+// unpacking it.  The config is checked against the manifest when runRestore
+// unpacks the archive, before anything is changed.
+func archivedConfig(p string) ([]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in the backup archive", archConfig)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Clean(h.Name) == archConfig && h.Typeflag == tar.TypeReg {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// extractArchive writes the regular files in a gzip compressed tar archive
+// under dst and returns the size and checksum of each one
+func extractArchive(r io.Reader, dst string) (map[string]manifestFile, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	got := make(map[string]manifestFile)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return got, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Archives written by godojo backup only have regular files with relative paths
+		name := path.Clean(h.Name)
+		if h.Typeflag != tar.TypeReg || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("unexpected entry %s in the backup archive", h.Name)
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(target), 0700)
+		if err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.New()
+		n, err := io.Copy(io.MultiWriter(out, sum), tr)
+		out.Close()
+		if err != nil {
+			return nil, err
+		}
+		got[name] = manifestFile{Path: name, Size: n, SHA256: hex.EncodeToString(sum.Sum(nil))}
+	}
+}
+
+// verifyManifest reads the manifest from an extracted archive and checks
+// that the extracted files are exactly the ones it lists
+func verifyManifest(dir string, got map[string]manifestFile) (backupManifest, error) {
+	var m backupManifest
+	b, err := os.ReadFile(filepath.Join(dir, archManifest))
+	if err != nil {
+		return m, fmt.Errorf("the backup archive has no %s: %w", archManifest, err)
+	}
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return m, fmt.Errorf("unable to read %s: %w", archManifest, err)
+	}
+	if m.Format < 1 || m.Format > archiveFormat {
+		return m, fmt.Errorf("the backup archive is format %d, this godojo reads up to format %d", m.Format, archiveFormat)
+	}
+
+	delete(got, archManifest)
+	for _, mf := range m.Files {
+		g, ok := got[mf.Path]
+		if !ok {
+			return m, fmt.Errorf("%s is in the manifest but missing from the backup archive", mf.Path)
+		}
+		if g != mf {
+			return m, fmt.Errorf("%s doesn't match the checksum in the manifest, the backup archive is corrupt", mf.Path)
+		}
+		delete(got, mf.Path)
+	}
+	for p := range got {
+		return m, fmt.Errorf("%s is in the backup archive but not in the manifest", p)
+	}
+	if _, ok := fileInManifest(m, dbArchivePath(m.Engine)); !ok {
+		return m, errors.New("the backup archive has no database dump")
+	}
+
+	return m, nil
+}
+
+// fileInManifest returns the manifest entry for the file at p
+func fileInManifest(m backupManifest, p string) (manifestFile, bool) {
+	for _, mf := range m.Files {
+		if mf.Path == p {
+			return mf, true
+		}
+	}
+
+	return manifestFile{}, false
+}
+
+// dbFamily returns the engine whose dumps can be loaded by engine since
+// MariaDB loads MySQL dumps and the reverse
+func dbFamily(engine string) string {
+	if engine == "MariaDB" {
+		return "MySQL"
+	}
+
+	return engine
+}
+
+// needMigrate returns true if restoring a backup of DefectDojo version from
+// to installed version to needs database migrations.  Migrations are run if
+// either version is unknown since they do nothing on an up to date database.
+func needMigrate(from string, to string) (bool, error) {
+	if from == "" || to == "" {
+		return true, nil
+	}
+	switch compareVersions(to, from) {
+	case -1:
+		return false, fmt.Errorf("The backup is of DefectDojo %s which is newer than the installed %s. "+
+			"Set Install.Version to %s or newer to restore it", from, to, from)
+	case 1:
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// compareVersions compares dotted versions like 2.4.1 returning -1, 0 or 1
+// if a is older, the same or newer than b
+func compareVersions(a string, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
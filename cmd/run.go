@@ -21,38 +21,23 @@ func run(d *DDConfig) {
 	// Check embedded
 	embdCk(d)
 
-	// Check install OS
-	osTarget := checkOS(d)
-
-	// Bootstrap install
-	bootstrapInstall(d, &osTarget)
-
-	// Validate Python version
-	validPython(d)
-
-	// Download DefectDojo release or source
-	downloadDojo(d)
-
-	// Install OS packges need by DefectDojo
-	prepOSForDojo(d, &osTarget)
+	// Only one godojo run at a time for an Install.Root
+	lockRoot(d)
 
-	// Install DB if needed
-	installDBForDojo(d, &osTarget)
-
-	// Prepare the DB for DefectDojo
-	prepDBForDojo(d, &osTarget)
-
-	// Prepare for Django - virtenv, etc
-	// TODO Convert to Commandeer
-	prepDjango(d, &osTarget)
-
-	// Create settings.py
-	createSettings(d, &osTarget)
+	// Check install OS
+	var osTarget targetOS
+	d.inPhase("checkos", func() { osTarget = checkOS(d) })
 
-	// Setup DefectDojo
-	setupDefectDojo(d, &osTarget)
+	// Run the install phases selected with --only and --skip, all by default
+	runPhases(d, &osTarget)
+	unusedPins(d)
 
 	d.statusMsg(fmt.Sprintf("\nSuccessfully installed DefectDojo using godojo version %+v", d.ver))
+	if d.dbBackup != "" {
+		d.statusMsg(fmt.Sprintf("The database dropped during the install was backed up to %s", d.dbBackup))
+	}
+	d.releaseLocks()
+	d.summary("ok", "DefectDojo installed")
 }
 
 func setCmdLogging(d *DDConfig) *log.Logger {
@@ -71,7 +56,7 @@ func setCmdLogging(d *DDConfig) *log.Logger {
 		fmt.Println("##############################################################################")
 		fmt.Println("")
 		fmt.Println("Log files are required for the install, exiting install")
-		os.Exit(1)
+		d.exitWith(exitGeneral)
 	}
 	//cmdLogger = cmdFile
 	d.traceMsg(fmt.Sprintf("Successfully created OS Command log file at %+v", cmdPath))
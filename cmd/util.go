@@ -29,7 +29,7 @@ func untar(d *DDConfig, dst string, r io.Reader) error {
 		err := gzr.Close()
 		if err != nil {
 			d.errorMsg(fmt.Sprintf("Unable to close the gzip reader\nError was %v", err))
-			os.Exit(1)
+			d.exitWith(exitGeneral)
 		}
 	}()
 
@@ -96,7 +96,7 @@ func embdCk(d *DDConfig) {
 		err := extr(d)
 		if err != nil {
 			fmt.Printf("Configuration has Embd = %v but no embedded files available\n", d.conf.Options.Embd)
-			os.Exit(1)
+			d.exitWith(exitGeneral)
 		}
 		os.Exit(0)
 	}
@@ -117,7 +117,7 @@ func extr(d *DDConfig) error {
 		// Embedded file was not found.
 		fmt.Println("Unable to extract embedded config file")
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		d.exitWith(exitGeneral)
 	}
 
 	if strings.Compare(d.conf.Options.Key, "jahtauCaizahXae4doh8oKoo") != 0 {
@@ -322,7 +322,7 @@ func hanc(d *DDConfig, s []string) error {
 		}
 		d.errorMsg(emsg)
 		fmt.Println("Unable to complete installation.  Quitting")
-		os.Exit(1)
+		d.exitWith(exitUnsupported)
 	}
 	return nil
 }
@@ -348,14 +348,14 @@ func hane(d *DDConfig, s []string) error {
 			tempLog,
 			t[j],
 			fmt.Sprintf("Unable to run command: %v", t[j]),
-			true)
+			true, 0)
 	}
 	d.traceMsg("Final change of ownership for " + d.conf.Install.Root)
 	sendCmd(d,
 		tempLog,
 		"chown -R "+d.conf.Install.OS.User+":"+d.conf.Install.OS.Group+" "+d.conf.Install.Root,
 		"Unable to set file ownership for "+d.conf.Install.Root,
-		false)
+		false, 0)
 
 	return nil
 }
@@ -371,7 +371,7 @@ func hanz(d *DDConfig, s []string) error {
 		return err
 	}
 	zc := d.otdir + "gdj-runner " + d.otdir
-	sendCmd(d, tempLog, zc, "Error running extract command", false)
+	sendCmd(d, tempLog, zc, "Error running extract command", false, 0)
 	return nil
 }
 
@@ -389,12 +389,14 @@ func clup(d *DDConfig) error {
 func escSpCar(s string) string {
 	// Replace special characters that cause issues when exec'ing in Bash
 
-	// Replace $ with \$
+	// Replace \ with \\
 	s = strings.ReplaceAll(s, "\\", "\\\\")
 	// Replace $ with \$
 	s = strings.ReplaceAll(s, "$", "\\$")
-	// Replace $ with \$
+	// Replace ` with \`
 	s = strings.ReplaceAll(s, "`", "\\`")
+	// Replace " with \"
+	s = strings.ReplaceAll(s, "\"", "\\\"")
 
 	return s
 }
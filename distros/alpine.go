@@ -0,0 +1,11 @@
+package distros
+
+// Alpine uses the alpine-*.yml command packs with point releases like 3.19.1
+// using the commands for 3.19
+func init() {
+	Register(&packDistro{
+		name:    "Alpine",
+		ids:     map[string]string{"alpine": "Alpine"},
+		release: majorMinorRel,
+	})
+}
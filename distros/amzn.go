@@ -0,0 +1,10 @@
+package distros
+
+// Amazon Linux uses the amzn-*.yml command packs.  It's Fedora-based with
+// its own packages so it isn't matched as RHEL compatible.
+func init() {
+	Register(&packDistro{
+		name: "Amzn",
+		ids:  map[string]string{"amzn": "Amazon Linux"},
+	})
+}
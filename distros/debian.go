@@ -0,0 +1,11 @@
+package distros
+
+// Debian uses the debian-*.yml command packs with point releases like 12.5
+// using the commands for 12
+func init() {
+	Register(&packDistro{
+		name:    "Debian",
+		ids:     map[string]string{"debian": "Debian"},
+		release: majorRel,
+	})
+}
@@ -2,21 +2,111 @@ package distros
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	c "github.com/mtesauro/commandeer"
 )
 
-func CmdsForTarget(cp *c.CmdPkg, t string) ([]c.SingleCmd, error) {
-	// Cycle through Ubuntu install targets
-	for k := range cp.Targets {
-		if strings.Compare(
-			strings.ToLower(cp.Targets[k].ID),
-			strings.ToLower(t)) == 0 {
-			// Return the commands matching that target
-			return cp.Targets[k].PkgCmds, nil
+// releasesFor returns the install targets of a registered distro
+func releasesFor(distro string) []c.Target {
+	d, ok := Lookup(distro)
+	if !ok {
+		return nil
+	}
+
+	return d.Targets()
+}
+
+// Supported returns true if there are commands for the OS target e.g. Ubuntu:22.04
+func Supported(t string) bool {
+	distro, _, _ := strings.Cut(t, ":")
+	for _, r := range releasesFor(distro) {
+		if strings.EqualFold(r.ID, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClosestRelease takes an OS target like Ubuntu:24.10 and returns the ID of
+// the supported target for that distro with the closest release.  The newest
+// supported release older than the target is preferred, falling back to the
+// oldest supported release newer than the target.
+func ClosestRelease(t string) (string, error) {
+	distro, rel, _ := strings.Cut(t, ":")
+	want, err := relParts(rel)
+	if err != nil {
+		return "", fmt.Errorf("Unable to parse release %s of OS target %s\n", rel, t)
+	}
+
+	older, newer := -1, -1
+	rs := releasesFor(distro)
+	for k := range rs {
+		have, err := relParts(rs[k].Release)
+		if err != nil {
+			continue
+		}
+		if relCompare(have, want) <= 0 {
+			if older < 0 || relCompare(have, mustRelParts(rs[older].Release)) > 0 {
+				older = k
+			}
+			continue
+		}
+		if newer < 0 || relCompare(have, mustRelParts(rs[newer].Release)) < 0 {
+			newer = k
+		}
+	}
+
+	switch {
+	case older >= 0:
+		return rs[older].ID, nil
+	case newer >= 0:
+		return rs[newer].ID, nil
+	}
+
+	return "", fmt.Errorf("No supported releases found for OS target %s\n", t)
+}
+
+// relParts splits a release like 22.04 into its numeric parts
+func relParts(r string) ([]int, error) {
+	var p []int
+	for _, s := range strings.Split(r, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, n)
+	}
+
+	return p, nil
+}
+
+// mustRelParts is relParts for releases that have already been parsed
+func mustRelParts(r string) []int {
+	p, _ := relParts(r)
+
+	return p
+}
+
+// relCompare returns -1, 0 or 1 if release a is older, the same or newer than release b
+func relCompare(a []int, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
 		}
 	}
 
-	return make([]c.SingleCmd, 1), fmt.Errorf("Unable to find commands for OS target %s\n", t)
+	return 0
 }
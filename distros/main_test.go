@@ -0,0 +1,33 @@
+package distros
+
+import (
+	"testing"
+)
+
+func TestClosestRelease(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"Ubuntu:24.10", "Ubuntu:24.04"},
+		{"ubuntu:23.04", "Ubuntu:22.04"},
+		{"Ubuntu:20.04", "Ubuntu:21.04"},
+		{"Debian:13", "Debian:12"},
+		{"RHEL:10", "RHEL:9"},
+	}
+	for _, tt := range tests {
+		got, err := ClosestRelease(tt.target)
+		if err != nil {
+			t.Errorf("ClosestRelease(%s) returned error %v", tt.target, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ClosestRelease(%s): expecting %s, got %s", tt.target, tt.want, got)
+		}
+	}
+
+	_, err := ClosestRelease("Fedora:39")
+	if err == nil {
+		t.Errorf("Expecting an error for an unsupported distro")
+	}
+}
@@ -0,0 +1,242 @@
+package distros
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	c "github.com/mtesauro/commandeer"
+	"gopkg.in/yaml.v2"
+)
+
+// Command packs for each supported distro and release, see packs/README.md
+//
+//go:embed packs/*.yml
+var embedded embed.FS
+
+// cmdPack is a distro command pack as written in YAML
+type cmdPack struct {
+	ID         string                     `yaml:"id"`         // Install target e.g. Ubuntu:22.04
+	Distro     string                     `yaml:"distro"`     // Distro name e.g. Ubuntu
+	Release    string                     `yaml:"release"`    // Distro release e.g. 22.04
+	OS         string                     `yaml:"os"`         // Operating system e.g. Linux
+	Shell      string                     `yaml:"shell"`      // Shell to run the commands in e.g. bash
+	Extends    string                     `yaml:"extends"`    // Optional ID of the pack to inherit commands from
+	DBVersions map[string]string          `yaml:"dbversions"` // Default major version by database engine e.g. postgresql: "14"
+	Labels     map[string][]packCmd       `yaml:"labels"`     // Commands by label, replacing any inherited commands
+	Overrides  map[string]map[int]packCmd `yaml:"overrides"`  // Inherited commands to replace by label and step starting at 1
+	Append     map[string][]packCmd       `yaml:"append"`     // Commands to add after the inherited commands by label
+}
+
+// packCmd is a single command in a command pack
+type packCmd struct {
+	Cmd     string `yaml:"cmd"`
+	Errmsg  string `yaml:"errmsg"`
+	Hard    bool   `yaml:"hard"`
+	Timeout int    `yaml:"timeout"` // Seconds, 0 for no timeout
+}
+
+// Command packs keyed by lower case ID and the commands for each pack after
+// inheritance keyed by lower case ID then label
+var (
+	packs    = map[string]*cmdPack{}
+	resolved = map[string]map[string][]c.SingleCmd{}
+)
+
+func init() {
+	err := loadPacks(embedded, "packs")
+	if err == nil {
+		err = resolvePacks()
+	}
+	if err != nil {
+		// The embedded packs are part of the build so this is a bug in godojo
+		panic(fmt.Sprintf("Unable to load the embedded command packs: %+v", err))
+	}
+}
+
+// LoadPackDir loads the command packs in dir, replacing embedded packs with
+// the same ID and adding any new install targets
+func LoadPackDir(dir string) error {
+	err := loadPacks(os.DirFS(dir), ".")
+	if err != nil {
+		return err
+	}
+
+	return resolvePacks()
+}
+
+// loadPacks reads the *.yml command packs in dir of fsys
+func loadPacks(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.yml"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("No command packs (*.yml) found in %s\n", dir)
+	}
+
+	for _, f := range files {
+		b, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+		p := cmdPack{}
+		err = yaml.UnmarshalStrict(b, &p)
+		if err != nil {
+			return fmt.Errorf("Unable to parse command pack %s: %+v\n", f, err)
+		}
+		if p.ID == "" || p.Distro == "" || p.Release == "" {
+			return fmt.Errorf("Command pack %s must set id, distro and release\n", f)
+		}
+		if !strings.EqualFold(p.ID, p.Distro+":"+p.Release) {
+			return fmt.Errorf("Command pack %s has id %s which doesn't match distro:release\n", f, p.ID)
+		}
+		packs[strings.ToLower(p.ID)] = &p
+	}
+
+	return nil
+}
+
+// resolvePacks applies extends, overrides and append for every loaded pack
+func resolvePacks() error {
+	all := map[string]map[string][]c.SingleCmd{}
+	for id := range packs {
+		_, err := resolvePack(id, all, map[string]bool{})
+		if err != nil {
+			return err
+		}
+	}
+	resolved = all
+
+	return nil
+}
+
+// resolvePack returns the commands for a pack after inheritance, using seen
+// to catch packs that extend each other
+func resolvePack(id string, all map[string]map[string][]c.SingleCmd, seen map[string]bool) (map[string][]c.SingleCmd, error) {
+	if r, ok := all[id]; ok {
+		return r, nil
+	}
+	p, ok := packs[id]
+	if !ok {
+		return nil, fmt.Errorf("Unable to find command pack %s\n", id)
+	}
+	if seen[id] {
+		return nil, fmt.Errorf("Command pack %s extends itself\n", p.ID)
+	}
+	seen[id] = true
+
+	// Start from a copy of the parent's commands
+	r := map[string][]c.SingleCmd{}
+	if p.Extends != "" {
+		parent, err := resolvePack(strings.ToLower(p.Extends), all, seen)
+		if err != nil {
+			return nil, fmt.Errorf("Command pack %s extends %s: %+v", p.ID, p.Extends, err)
+		}
+		for l, cmds := range parent {
+			r[l] = append([]c.SingleCmd{}, cmds...)
+		}
+	}
+
+	for l, cmds := range p.Labels {
+		r[l] = singleCmds(cmds)
+	}
+	for l, steps := range p.Overrides {
+		for n, cmd := range steps {
+			if n < 1 || n > len(r[l]) {
+				return nil, fmt.Errorf("Command pack %s overrides step %d of %s which has %d steps\n", p.ID, n, l, len(r[l]))
+			}
+			r[l][n-1] = singleCmds([]packCmd{cmd})[0]
+		}
+	}
+	for l, cmds := range p.Append {
+		r[l] = append(r[l], singleCmds(cmds)...)
+	}
+
+	// An empty list of commands means the label isn't supported
+	for l := range r {
+		if len(r[l]) == 0 {
+			delete(r, l)
+		}
+	}
+	all[id] = r
+
+	return r, nil
+}
+
+// singleCmds converts commands from a pack to commandeer commands
+func singleCmds(cmds []packCmd) []c.SingleCmd {
+	sc := make([]c.SingleCmd, 0, len(cmds))
+	for _, cmd := range cmds {
+		sc = append(sc, c.SingleCmd{
+			Cmd:     cmd.Cmd,
+			Errmsg:  cmd.Errmsg,
+			Hard:    cmd.Hard,
+			Timeout: time.Duration(cmd.Timeout) * time.Second,
+		})
+	}
+
+	return sc
+}
+
+// packTarget returns the install target for a pack without any commands
+func packTarget(p *cmdPack) c.Target {
+	return c.Target{
+		ID:      p.ID,
+		Distro:  p.Distro,
+		Release: p.Release,
+		OS:      p.OS,
+		Shell:   p.Shell,
+	}
+}
+
+// dbLabel returns the pack label for a database label e.g. installdb-mysql
+func dbLabel(label string, db string) string {
+	return label + "-" + strings.ToLower(db)
+}
+
+// packCmds returns a copy of the commands for label from the pack for target t
+func packCmds(t string, label string) ([]c.SingleCmd, error) {
+	if _, ok := packs[strings.ToLower(t)]; !ok {
+		return nil, fmt.Errorf("Unable to find commands for target %s\n", t)
+	}
+	cmds, ok := resolved[strings.ToLower(t)][label]
+	if !ok {
+		return nil, fmt.Errorf("Unable to find a set of commands for the label %s and target %s\n", label, t)
+	}
+
+	// Copy the commands since config values are injected into them later
+	return append([]c.SingleCmd{}, cmds...), nil
+}
+
+// packDBVersion returns the default major version of a database engine for
+// target t, inherited from the packs it extends if not set
+func packDBVersion(t string, engine string) string {
+	p, ok := packs[strings.ToLower(t)]
+	for ok {
+		if v, set := p.DBVersions[strings.ToLower(engine)]; set {
+			return v
+		}
+		p, ok = packs[strings.ToLower(p.Extends)]
+	}
+
+	return ""
+}
+
+// packReleases returns the install targets with command packs for a distro
+func packReleases(distro string) []c.Target {
+	var ts []c.Target
+	for _, p := range packs {
+		if strings.EqualFold(p.Distro, distro) {
+			ts = append(ts, packTarget(p))
+		}
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].ID < ts[j].ID })
+
+	return ts
+}
@@ -0,0 +1,92 @@
+package distros
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedPacks(t *testing.T) {
+	labels := []string{"bootstrap", "installerprep", "prepdjango", "createsettings", "setupdojo",
+		"installdb-postgresql", "startdb-postgresql", "installdbclient-postgresql", "installdbclient-mysql",
+		"installdb-mariadb", "startdb-mariadb", "installdbclient-mariadb"}
+	for id, p := range packs {
+		for _, l := range labels {
+			if len(resolved[id][l]) == 0 {
+				t.Errorf("Command pack %s has no commands for %s", p.ID, l)
+			}
+		}
+	}
+
+	// Ubuntu 24.04 extends 22.04, overriding the default PostgreSQL version
+	ubuntu, _ := Lookup("ubuntu")
+	cmds, err := ubuntu.DBCommands("installdbclient", "Ubuntu:24.04", "PostgreSQL")
+	if err != nil {
+		t.Fatalf("DBCommands returned error %v", err)
+	}
+	if got := cmds[0].Cmd; got != "pkgmgr apt install postgresql-client-{conf.Install.DB.Version}" {
+		t.Errorf("Expecting the versioned PostgreSQL client, got %s", got)
+	}
+	if got := ubuntu.DBVersion("Ubuntu:24.04", "PostgreSQL"); got != "16" {
+		t.Errorf("Expecting PostgreSQL 16 for Ubuntu:24.04, got %s", got)
+	}
+
+	// Alpine 3.18 inherits its PostgreSQL version and has no PGDG repo
+	alpine, _ := Lookup("alpine")
+	if got := alpine.DBVersion("Alpine:3.18", "PostgreSQL"); got != "15" {
+		t.Errorf("Expecting PostgreSQL 15 for Alpine:3.18, got %s", got)
+	}
+	if _, err := alpine.DBCommands("installdb", "Alpine:3.18", "PostgreSQL-PGDG"); err == nil {
+		t.Errorf("Expecting no PGDG install commands for Alpine:3.18")
+	}
+}
+
+func TestLoadPackDir(t *testing.T) {
+	dir := t.TempDir()
+	pack := `id: Ubuntu:25.04
+distro: Ubuntu
+release: "25.04"
+os: Linux
+shell: bash
+extends: Ubuntu:24.04
+overrides:
+  bootstrap:
+    2:
+      cmd: "pkgmgr apt upgrade"
+      errmsg: "Site specific upgrade"
+      hard: false
+`
+	err := os.WriteFile(filepath.Join(dir, "ubuntu-25.04.yml"), []byte(pack), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadPackDir(dir)
+	if err != nil {
+		t.Fatalf("LoadPackDir returned error %v", err)
+	}
+	defer func() {
+		delete(packs, "ubuntu:25.04")
+		_ = resolvePacks()
+	}()
+
+	if !Supported("ubuntu:25.04") {
+		t.Errorf("Expecting Ubuntu:25.04 to be supported after loading %s", dir)
+	}
+	ubuntu, _ := Lookup("ubuntu")
+	cmds, err := ubuntu.Commands("bootstrap", "Ubuntu:25.04")
+	if err != nil {
+		t.Fatalf("Commands returned error %v", err)
+	}
+	if len(cmds) != len(resolved["ubuntu:24.04"]["bootstrap"]) || cmds[1].Errmsg != "Site specific upgrade" {
+		t.Errorf("Unexpected bootstrap commands for Ubuntu:25.04: %+v", cmds)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "ubuntu-25.04.yml"), []byte(strings.Replace(pack, "extends: Ubuntu:24.04", "extends: Ubuntu:25.04", 1)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if LoadPackDir(dir) == nil {
+		t.Errorf("Expecting an error for a pack that extends itself")
+	}
+}
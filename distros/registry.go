@@ -0,0 +1,147 @@
+package distros
+
+import (
+	"fmt"
+	"strings"
+
+	c "github.com/mtesauro/commandeer"
+)
+
+// Distro is a Linux distro godojo can install DefectDojo on
+type Distro interface {
+	Name() string                                              // Distro name used in install targets e.g. ubuntu
+	Detect(id, release, like string) (Detected, bool)          // Match the ID, VERSION_ID and ID_LIKE from /etc/os-release
+	Targets() []c.Target                                       // Supported install targets
+	Commands(label, t string) ([]c.SingleCmd, error)           // Commands for a label e.g. bootstrap and target e.g. ubuntu:22.04
+	DBCommands(label, t, engine string) ([]c.SingleCmd, error) // Commands for a database label e.g. installdb and engine
+	DBVersion(t, engine string) string                         // Default major version of a database engine e.g. 14
+	Prepare(variant, release string) []c.SingleCmd             // Commands run before bootstrap for the os-release ID of the install
+}
+
+// Detected is an install target matched from /etc/os-release
+type Detected struct {
+	Distro  string // Distro name used in install targets e.g. rhel
+	Release string // Release used in install targets e.g. 9 for 9.3
+	Variant string // os-release ID e.g. rocky
+	Name    string // Human readable name e.g. Rocky Linux
+}
+
+// Registered distros
+var registry []Distro
+
+// Register adds a distro to those godojo can detect and install on
+func Register(d Distro) {
+	registry = append(registry, d)
+}
+
+// Lookup returns the registered distro for a name e.g. ubuntu
+func Lookup(name string) (Distro, bool) {
+	for _, d := range registry {
+		if strings.EqualFold(d.Name(), name) {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
+
+// Detect returns the registered distro matching the ID, VERSION_ID and
+// ID_LIKE from /etc/os-release.  Exact IDs are matched before ID_LIKE so
+// the order distros are registered doesn't matter.
+func Detect(id, release, like string) (Distro, Detected, bool) {
+	for _, d := range registry {
+		if det, ok := d.Detect(id, release, ""); ok {
+			return d, det, true
+		}
+	}
+	if like == "" {
+		return nil, Detected{}, false
+	}
+	for _, d := range registry {
+		if det, ok := d.Detect(id, release, like); ok {
+			return d, det, true
+		}
+	}
+
+	return nil, Detected{}, false
+}
+
+// packDistro is a Distro using the command packs for its name
+type packDistro struct {
+	name    string                                      // Distro in the command packs e.g. Ubuntu
+	ids     map[string]string                           // os-release IDs and their human readable names
+	likes   []string                                    // ID_LIKE values for distros not in ids
+	release func(string) string                         // Optional, converts VERSION_ID to a pack release
+	prepare func(variant, release string) []c.SingleCmd // Optional, commands run before bootstrap
+}
+
+func (p *packDistro) Name() string {
+	return strings.ToLower(p.name)
+}
+
+func (p *packDistro) Detect(id, release, like string) (Detected, bool) {
+	name, ok := p.ids[id]
+	if !ok && like != "" {
+		for _, l := range strings.Fields(like) {
+			for _, want := range p.likes {
+				if l == want {
+					name, ok = id+" ("+p.name+"-like)", true
+				}
+			}
+		}
+	}
+	if !ok {
+		return Detected{}, false
+	}
+	if p.release != nil {
+		release = p.release(release)
+	}
+
+	return Detected{Distro: p.Name(), Release: release, Variant: id, Name: name}, true
+}
+
+func (p *packDistro) Targets() []c.Target {
+	return packReleases(p.name)
+}
+
+func (p *packDistro) Commands(label, t string) ([]c.SingleCmd, error) {
+	distro, _, _ := strings.Cut(t, ":")
+	if !strings.EqualFold(distro, p.name) {
+		return nil, fmt.Errorf("Target %s isn't a %s release\n", t, p.name)
+	}
+
+	return packCmds(t, label)
+}
+
+func (p *packDistro) DBCommands(label, t, engine string) ([]c.SingleCmd, error) {
+	return p.Commands(dbLabel(label, engine), t)
+}
+
+func (p *packDistro) DBVersion(t, engine string) string {
+	return packDBVersion(t, engine)
+}
+
+func (p *packDistro) Prepare(variant, release string) []c.SingleCmd {
+	if p.prepare == nil {
+		return []c.SingleCmd{}
+	}
+
+	return p.prepare(variant, release)
+}
+
+// majorRel returns the major version of a release e.g. 9 for 9.3
+func majorRel(r string) string {
+	major, _, _ := strings.Cut(r, ".")
+
+	return major
+}
+
+// majorMinorRel returns the major and minor version of a release e.g. 3.19 for 3.19.1
+func majorMinorRel(r string) string {
+	p := strings.SplitN(r, ".", 3)
+	if len(p) < 2 {
+		return p[0]
+	}
+
+	return p[0] + "." + p[1]
+}
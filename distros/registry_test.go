@@ -0,0 +1,35 @@
+package distros
+
+import (
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		id, release, like string
+		want              Detected
+	}{
+		{"ubuntu", "22.04", "debian", Detected{"ubuntu", "22.04", "ubuntu", "Ubuntu"}},
+		{"debian", "12.5", "", Detected{"debian", "12", "debian", "Debian"}},
+		{"rocky", "9.3", "rhel centos fedora", Detected{"rhel", "9", "rocky", "Rocky Linux"}},
+		{"eurolinux", "8.9", "rhel fedora centos", Detected{"rhel", "8", "eurolinux", "eurolinux (RHEL-like)"}},
+		{"amzn", "2023", "fedora", Detected{"amzn", "2023", "amzn", "Amazon Linux"}},
+		{"opensuse-leap", "15.5", "suse opensuse", Detected{"suse", "15", "opensuse-leap", "openSUSE Leap"}},
+		{"alpine", "3.19.1", "", Detected{"alpine", "3.19", "alpine", "Alpine"}},
+	}
+	for _, tt := range tests {
+		_, got, ok := Detect(tt.id, tt.release, tt.like)
+		if !ok {
+			t.Errorf("Detect(%s, %s) didn't match a distro", tt.id, tt.release)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Detect(%s, %s): expecting %+v, got %+v", tt.id, tt.release, tt.want, got)
+		}
+	}
+
+	_, _, ok := Detect("fedora", "39", "")
+	if ok {
+		t.Errorf("Expecting Fedora to not match a distro")
+	}
+}
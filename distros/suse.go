@@ -0,0 +1,48 @@
+package distros
+
+import (
+	c "github.com/mtesauro/commandeer"
+)
+
+// SLES and openSUSE Leap use the suse-*.yml command packs
+func init() {
+	Register(&packDistro{
+		name: "SUSE",
+		ids: map[string]string{
+			"sles":          "SLES",
+			"opensuse-leap": "openSUSE Leap",
+		},
+		release: majorRel,
+		prepare: func(variant, release string) []c.SingleCmd {
+			return SUSEVariantCmds(variant)
+		},
+	})
+}
+
+// SUSEVariantCmds returns the commands needed before bootstrapping SLES or
+// openSUSE Leap, keyed off the os-release ID.  SLES needs the modules with
+// Python 3.11 and nodejs enabled while Leap has them in its default repos.
+func SUSEVariantCmds(variant string) []c.SingleCmd {
+	if variant != "sles" {
+		return []c.SingleCmd{}
+	}
+
+	return []c.SingleCmd{
+		c.SingleCmd{
+			Cmd:        "SUSEConnect -p sle-module-python3/$(. /etc/os-release && echo $VERSION_ID)/$(arch)",
+			Errmsg:     "Unable to enable the Python 3 module, continuing anyway",
+			Hard:       false,
+			Timeout:    0,
+			BeforeText: "",
+			AfterText:  "",
+		},
+		c.SingleCmd{
+			Cmd:        "SUSEConnect -p sle-module-web-scripting/$(. /etc/os-release && echo $VERSION_ID)/$(arch)",
+			Errmsg:     "Unable to enable the Web and Scripting module, continuing anyway",
+			Hard:       false,
+			Timeout:    0,
+			BeforeText: "",
+			AfterText:  "",
+		},
+	}
+}
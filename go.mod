@@ -4,10 +4,13 @@ go 1.19
 
 require (
 	github.com/briandowns/spinner v1.6.1
+	github.com/go-sql-driver/mysql v1.7.1
+	github.com/lib/pq v1.10.9
 	github.com/mtesauro/commandeer v1.1.4
 	github.com/spf13/viper v1.4.0
 	golang.org/x/text v0.13.0
 	gopkg.in/src-d/go-git.v4 v4.12.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -36,5 +39,4 @@ require (
 	golang.org/x/sys v0.13.0 // indirect
 	gopkg.in/src-d/go-billy.v4 v4.3.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
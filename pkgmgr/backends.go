@@ -0,0 +1,136 @@
+package pkgmgr
+
+import "strings"
+
+// backend holds the commands and messages for a package manager
+type backend struct {
+	name     string
+	update   string                            // Refresh the package database
+	upgrade  string                            // Upgrade all installed packages
+	security string                            // Upgrade installed packages with security updates, empty if unsupported
+	install  string                            // Install, package names are appended
+	query    string                            // Print the installed version, %[1]s is the package name
+	version  func(name, out string) string     // Parse the installed version from the output of query
+	pin      func(name, version string) string // Format a package pinned to a version
+	lockMsgs []string                          // Output when another process holds the lock
+	netMsgs  []string                          // Output for transient mirror or network errors
+}
+
+// Supported package managers
+var backends = map[string]backend{
+	"apt": {
+		name:    "apt",
+		update:  "DEBIAN_FRONTEND=noninteractive apt-get update",
+		upgrade: "DEBIAN_FRONTEND=noninteractive apt-get -y upgrade",
+		// apt has no security only upgrade so use the packages from the -security suites
+		security: "apt-get -s upgrade | awk '/^Inst .*-security/ {print $2}' | " +
+			"xargs -r env DEBIAN_FRONTEND=noninteractive apt-get -y --only-upgrade install",
+		install: "DEBIAN_FRONTEND=noninteractive apt-get -y -o Dpkg::Options::=\"--force-confdef\" " +
+			"-o Dpkg::Options::=\"--force-confold\" install",
+		query: "dpkg-query -W -f='${Status} ${Version}' %[1]s",
+		version: func(name, out string) string {
+			// Packages removed but not purged are still known to dpkg
+			if !strings.HasPrefix(out, "install ok installed ") {
+				return ""
+			}
+			return strings.TrimPrefix(out, "install ok installed ")
+		},
+		pin: pinWith("="),
+		lockMsgs: []string{
+			"Could not get lock",
+			"Unable to acquire the dpkg frontend lock",
+			"Unable to lock directory",
+		},
+		netMsgs: []string{
+			"Temporary failure resolving",
+			"Failed to fetch",
+			"Could not connect to",
+			"Connection timed out",
+			"Hash Sum mismatch",
+		},
+	},
+	"dnf": {
+		name: "dnf",
+		// dnf returns a 100 exit code if updates are available
+		update:   "dnf check-update || [ $? -eq 100 ]",
+		upgrade:  "dnf update -y",
+		security: "dnf update -y --security",
+		install:  "dnf install -y",
+		query:    "rpm -q --qf '%%{VERSION}-%%{RELEASE}' --whatprovides %[1]s",
+		version:  rpmVersion,
+		pin:      pinWith("-"),
+		lockMsgs: []string{
+			"Waiting for process with pid",
+			"Failed to obtain the transaction lock",
+			"can't create transaction lock",
+		},
+		netMsgs: []string{
+			"Curl error",
+			"Cannot download",
+			"Failed to download metadata",
+			"No more mirrors to try",
+		},
+	},
+	"zypper": {
+		name:     "zypper",
+		update:   "zypper --non-interactive refresh",
+		upgrade:  "zypper --non-interactive update",
+		security: "zypper --non-interactive patch --category security",
+		install:  "zypper --non-interactive install",
+		query:    "rpm -q --qf '%%{VERSION}-%%{RELEASE}' --whatprovides %[1]s",
+		version:  rpmVersion,
+		pin:      pinWith("="),
+		lockMsgs: []string{
+			"System management is locked",
+		},
+		netMsgs: []string{
+			"Download (curl) error",
+			"Timeout exceeded",
+			"Valid metadata not found",
+		},
+	},
+	"apk": {
+		name:    "apk",
+		update:  "apk update",
+		upgrade: "apk upgrade",
+		install: "apk add",
+		query:   "apk info -e %[1]s >/dev/null && apk list -I %[1]s",
+		version: func(name, out string) string {
+			// Output is like git-2.43.0-r0 x86_64 {git} (GPL-2.0-only) [installed]
+			f := strings.Fields(out)
+			if len(f) == 0 {
+				return ""
+			}
+			return strings.TrimPrefix(f[0], name+"-")
+		},
+		pin: pinWith("="),
+		lockMsgs: []string{
+			"Unable to lock database",
+		},
+		netMsgs: []string{
+			"temporary error",
+			"network error",
+			"DNS lookup error",
+		},
+	},
+}
+
+// rpmVersion parses the version from rpm -q which exits non-zero for
+// packages that aren't installed
+func rpmVersion(name, out string) string {
+	if strings.Contains(out, "not installed") || strings.Contains(out, "no package provides") {
+		return ""
+	}
+
+	return out
+}
+
+// pinWith returns a function to pin a package to a version using sep
+func pinWith(sep string) func(string, string) string {
+	return func(name, version string) string {
+		if version == "" {
+			return name
+		}
+		return name + sep + version
+	}
+}
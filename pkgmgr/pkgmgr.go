@@ -0,0 +1,259 @@
+// Package pkgmgr runs OS package managers like apt and dnf for godojo's
+// command packs.  It waits for package manager locks held by other processes
+// e.g. unattended-upgrades, retries transient mirror errors, skips packages
+// that are already installed and records the packages it installs.
+package pkgmgr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prefix starts a command in a command pack that is run by this package
+// instead of being sent to bash e.g. "pkgmgr apt install git sudo"
+const Prefix = "pkgmgr"
+
+// Package manager actions
+const (
+	Update   = "update"   // Refresh the package database
+	Upgrade  = "upgrade"  // Upgrade all installed packages
+	Security = "security" // Upgrade installed packages with security updates
+	Install  = "install"  // Install one or more packages
+)
+
+// ErrLocked is wrapped by errors returned when another process held the
+// package manager lock for longer than Options.LockTimeout
+var ErrLocked = errors.New("package manager is locked by another process")
+
+// ErrNoSecurity is returned by SecurityUpgrade for package managers that
+// can't tell security updates apart from other updates
+var ErrNoSecurity = errors.New("package manager doesn't support security only updates")
+
+// Runner runs a command with bash and returns its combined stdout and stderr
+type Runner func(cmd string) (string, error)
+
+// Manager is an OS package manager
+type Manager interface {
+	Name() string                                    // Name of the package manager e.g. apt
+	Update() error                                   // Refresh the package database
+	Upgrade() error                                  // Upgrade all installed packages
+	SecurityUpgrade() error                          // Upgrade installed packages with security updates
+	Install(pkgs ...string) ([]Pkg, error)           // Install packages, returning those that were installed
+	Installed(name string) (version string, ok bool) // Installed version of a package
+}
+
+// Pkg is an OS package installed by a Manager
+type Pkg struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Manager string `json:"manager"`
+}
+
+// Options controls lock waiting and retries
+type Options struct {
+	LockTimeout time.Duration // How long to wait for another process to release the package manager lock
+	LockWait    time.Duration // How long to sleep between checks of the lock
+	Retries     int           // How many times to retry transient mirror or network errors
+	RetryWait   time.Duration // Wait before the first retry, doubled for each retry after that
+	Trace       func(string)  // Optional function for trace logging
+}
+
+// DefaultOptions returns the lock and retry options used by godojo
+func DefaultOptions() Options {
+	return Options{
+		LockTimeout: 10 * time.Minute,
+		LockWait:    10 * time.Second,
+		Retries:     3,
+		RetryWait:   15 * time.Second,
+	}
+}
+
+// Op is a package manager command from a command pack
+type Op struct {
+	Manager string
+	Action  string
+	Pkgs    []string
+}
+
+// Cmd returns the command pack string for a package manager action e.g.
+// Cmd("apt", Install, "git") returns "pkgmgr apt install git".  Packages can
+// be pinned to a version with name=version.
+func Cmd(mgr string, action string, pkgs ...string) string {
+	return strings.Join(append([]string{Prefix, mgr, action}, pkgs...), " ")
+}
+
+// Parse returns the package manager action for a command pack string and
+// true or false if the string wasn't created by Cmd
+func Parse(cmd string) (Op, bool) {
+	f := strings.Fields(cmd)
+	if len(f) < 3 || f[0] != Prefix {
+		return Op{}, false
+	}
+
+	return Op{Manager: f[1], Action: f[2], Pkgs: f[3:]}, true
+}
+
+// New returns the Manager for the named package manager
+func New(name string, run Runner, opts Options) (Manager, error) {
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown package manager %s", name)
+	}
+
+	return &manager{b: b, run: run, opts: opts}, nil
+}
+
+// Run runs a package manager command from a command pack, returning the
+// packages that were installed
+func Run(op Op, run Runner, opts Options) ([]Pkg, error) {
+	m, err := New(op.Manager, run, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Action {
+	case Update:
+		return nil, m.Update()
+	case Upgrade:
+		return nil, m.Upgrade()
+	case Security:
+		return nil, m.SecurityUpgrade()
+	case Install:
+		return m.Install(op.Pkgs...)
+	}
+
+	return nil, fmt.Errorf("unknown package manager action %s", op.Action)
+}
+
+// manager implements Manager for a backend
+type manager struct {
+	b    backend
+	run  Runner
+	opts Options
+}
+
+func (m *manager) Name() string {
+	return m.b.name
+}
+
+func (m *manager) Update() error {
+	return m.try(m.b.update)
+}
+
+func (m *manager) Upgrade() error {
+	return m.try(m.b.upgrade)
+}
+
+func (m *manager) SecurityUpgrade() error {
+	if m.b.security == "" {
+		return ErrNoSecurity
+	}
+
+	return m.try(m.b.security)
+}
+
+// pinMatches returns true if the installed version have satisfies the pinned
+// version want e.g. 18.19.0-1nodesource1 for 18.19.0 but not 1.20 for 1.2
+func pinMatches(have, want string) bool {
+	if have == want {
+		return true
+	}
+	for _, sep := range []string{"-", ".", "+"} {
+		if strings.HasPrefix(have, want+sep) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *manager) Install(pkgs ...string) ([]Pkg, error) {
+	// Skip packages already installed at the requested version
+	var names, need []string
+	for _, p := range pkgs {
+		name, want, _ := strings.Cut(p, "=")
+		have, ok := m.Installed(name)
+		if ok && (want == "" || pinMatches(have, want)) {
+			m.trace(fmt.Sprintf("%s %s is already installed, skipping", name, have))
+			continue
+		}
+		names = append(names, name)
+		need = append(need, m.b.pin(name, want))
+	}
+	if len(need) == 0 {
+		return nil, nil
+	}
+
+	err := m.try(m.b.install + " " + strings.Join(need, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the versions that were installed
+	done := make([]Pkg, 0, len(names))
+	for _, name := range names {
+		have, _ := m.Installed(name)
+		done = append(done, Pkg{Name: name, Version: have, Manager: m.b.name})
+	}
+
+	return done, nil
+}
+
+func (m *manager) Installed(name string) (string, bool) {
+	out, err := m.run(fmt.Sprintf(m.b.query, name))
+	if err != nil {
+		return "", false
+	}
+	v := m.b.version(name, strings.TrimSpace(out))
+
+	return v, v != ""
+}
+
+// try runs the command, waiting for the package manager lock and retrying
+// transient network errors
+func (m *manager) try(cmd string) error {
+	lockStart := time.Now()
+	retries := 0
+	wait := m.opts.RetryWait
+	for {
+		out, err := m.run(cmd)
+		if err == nil {
+			return nil
+		}
+
+		switch {
+		case matchAny(out, m.b.lockMsgs):
+			if time.Since(lockStart) >= m.opts.LockTimeout {
+				return fmt.Errorf("%w, waited %s for %s", ErrLocked, m.opts.LockTimeout, cmd)
+			}
+			m.trace(fmt.Sprintf("%s is locked by another process, waiting %s", m.b.name, m.opts.LockWait))
+			time.Sleep(m.opts.LockWait)
+		case matchAny(out, m.b.netMsgs) && retries < m.opts.Retries:
+			retries++
+			m.trace(fmt.Sprintf("Transient error from %s, retry %d of %d in %s", m.b.name, retries, m.opts.Retries, wait))
+			time.Sleep(wait)
+			wait *= 2
+		default:
+			return err
+		}
+	}
+}
+
+func (m *manager) trace(msg string) {
+	if m.opts.Trace != nil {
+		m.opts.Trace(msg)
+	}
+}
+
+// matchAny returns true if the output contains any of the messages
+func matchAny(out string, msgs []string) bool {
+	for _, msg := range msgs {
+		if strings.Contains(out, msg) {
+			return true
+		}
+	}
+
+	return false
+}
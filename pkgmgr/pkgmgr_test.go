@@ -0,0 +1,94 @@
+package pkgmgr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	op, ok := Parse(Cmd("apt", Install, "git", "sudo=1.9"))
+	if !ok || op.Manager != "apt" || op.Action != Install || strings.Join(op.Pkgs, " ") != "git sudo=1.9" {
+		t.Errorf("Parse(Cmd()) returned %+v, %v", op, ok)
+	}
+	if _, ok := Parse("apt-get install git"); ok {
+		t.Error("Parse() accepted a command not created by Cmd()")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	installed := map[string]string{"git": "1:2.43.0-1"}
+	locked := 1
+	var ran []string
+	run := func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, "dpkg-query") {
+			name := cmd[strings.LastIndex(cmd, " ")+1:]
+			if v, ok := installed[name]; ok {
+				return "install ok installed " + v, nil
+			}
+			return "", errors.New("exit status 1")
+		}
+		ran = append(ran, cmd)
+		if locked > 0 {
+			locked--
+			return "E: Could not get lock /var/lib/dpkg/lock-frontend", errors.New("exit status 100")
+		}
+		installed["sudo"] = "1.9.15"
+		return "", nil
+	}
+
+	m, err := New("apt", run, Options{LockTimeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := m.Install("git", "sudo")
+	if err != nil {
+		t.Fatalf("Install() returned %v", err)
+	}
+	if len(ran) != 2 || !strings.HasSuffix(ran[1], "install sudo") {
+		t.Errorf("Install() ran %q, wanted a retry after the lock installing only sudo", ran)
+	}
+	if len(pkgs) != 1 || pkgs[0] != (Pkg{Name: "sudo", Version: "1.9.15", Manager: "apt"}) {
+		t.Errorf("Install() recorded %+v", pkgs)
+	}
+}
+
+func TestPinMatches(t *testing.T) {
+	cases := []struct {
+		have, want string
+		match      bool
+	}{
+		{"18.19.0-1nodesource1", "18.19.0-1nodesource1", true},
+		{"18.19.0-1nodesource1", "18.19.0", true},
+		{"18.19.0-1nodesource1", "18.1", false},
+		{"1.20", "1.2", false},
+		{"1.2.5", "1.2", true},
+		{"15.6+deb12u1", "15.6", true},
+	}
+	for _, c := range cases {
+		if got := pinMatches(c.have, c.want); got != c.match {
+			t.Errorf("pinMatches(%q, %q) = %v", c.have, c.want, got)
+		}
+	}
+
+	// An installed 1.20 doesn't satisfy a pin of 1.2 so it's installed
+	var ran []string
+	run := func(cmd string) (string, error) {
+		if strings.HasPrefix(cmd, "dpkg-query") {
+			return "install ok installed 1.20", nil
+		}
+		ran = append(ran, cmd)
+		return "", nil
+	}
+	m, err := New("apt", run, Options{LockTimeout: time.Second, Retries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Install("sudo=1.2"); err != nil {
+		t.Fatalf("Install() returned %v", err)
+	}
+	if len(ran) != 1 || !strings.HasSuffix(ran[0], "install sudo=1.2") {
+		t.Errorf("Install() ran %q, wanted sudo=1.2 installed over 1.20", ran)
+	}
+}